@@ -85,14 +85,14 @@ func (s *JWTService) ValidateToken(tokenString string) (*Claims, error) {
 	})
 
 	if err != nil {
-		return nil, syserr.Wrap(err, syserr.UnauthorizedCode, "invalid token")
+		return nil, syserr.Wrap(err, syserr.UnauthorizedCode, "invalid token").WithPublicMessage("invalid token")
 	}
 
 	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
 		return claims, nil
 	}
 
-	return nil, syserr.New(syserr.UnauthorizedCode, "invalid token claims")
+	return nil, syserr.NewPublic(syserr.UnauthorizedCode, "invalid token claims")
 }
 
 // ValidateAccessToken validates specifically an access token
@@ -103,7 +103,7 @@ func (s *JWTService) ValidateAccessToken(tokenString string) (*Claims, error) {
 	}
 
 	if claims.Type != "access" {
-		return nil, syserr.New(syserr.UnauthorizedCode, "token is not an access token")
+		return nil, syserr.NewPublic(syserr.UnauthorizedCode, "token is not an access token")
 	}
 
 	return claims, nil
@@ -117,7 +117,7 @@ func (s *JWTService) ValidateRefreshToken(tokenString string) (*Claims, error) {
 	}
 
 	if claims.Type != "refresh" {
-		return nil, syserr.New(syserr.UnauthorizedCode, "token is not a refresh token")
+		return nil, syserr.NewPublic(syserr.UnauthorizedCode, "token is not a refresh token")
 	}
 
 	return claims, nil
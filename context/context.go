@@ -3,6 +3,7 @@ package context
 import (
 	"context"
 	"strconv"
+	"strings"
 
 	"github.com/duongptryu/gox/auth"
 	"github.com/duongptryu/gox/syserr"
@@ -22,6 +23,8 @@ const (
 	UserTypeKey contextKey = "userType"
 	// AuthClaimsKey is used for storing auth claims in context
 	AuthClaimsKey contextKey = "authClaims"
+	// TraceParentKey is used for storing W3C traceparent headers in context
+	TraceParentKey contextKey = "traceParent"
 )
 
 // Operation ID context utilities
@@ -64,6 +67,65 @@ func GetRequestID(ctx context.Context) string {
 	return ""
 }
 
+// Trace parent context utilities
+
+// WithTraceParent adds a W3C traceparent header value to the context, invalid values are ignored
+func WithTraceParent(ctx context.Context, traceParent string) context.Context {
+	if _, _, ok := ParseTraceParent(traceParent); !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, TraceParentKey, traceParent)
+}
+
+// GetTraceParent retrieves the W3C traceparent header value from context
+func GetTraceParent(ctx context.Context) string {
+	if value := ctx.Value(TraceParentKey); value != nil {
+		if traceParent, ok := value.(string); ok {
+			return traceParent
+		}
+	}
+	return ""
+}
+
+// ParseTraceParent returns the trace and parent span IDs of a W3C traceparent header value,
+// formatted as version-traceid-parentid-flags, e.g. 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+func ParseTraceParent(traceParent string) (traceID string, spanID string, ok bool) {
+	parts := strings.Split(traceParent, "-")
+	if len(parts) < 4 || !isLowerHex(parts[0], 2) || parts[0] == "ff" {
+		return "", "", false
+	}
+
+	// Version 00 has exactly four parts, later versions may add more
+	if parts[0] == "00" && len(parts) != 4 {
+		return "", "", false
+	}
+
+	traceID, spanID = parts[1], parts[2]
+	if !isLowerHex(traceID, 32) || !isLowerHex(spanID, 16) || !isLowerHex(parts[3], 2) {
+		return "", "", false
+	}
+
+	// All-zero IDs are invalid
+	if strings.Trim(traceID, "0") == "" || strings.Trim(spanID, "0") == "" {
+		return "", "", false
+	}
+
+	return traceID, spanID, true
+}
+
+func isLowerHex(value string, length int) bool {
+	if len(value) != length {
+		return false
+	}
+
+	for _, char := range value {
+		if (char < '0' || char > '9') && (char < 'a' || char > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
 // User ID context utilities
 
 // WithUserID adds a user ID to the context
@@ -87,7 +149,7 @@ func GetUserIDFromContext(ctx context.Context) string {
 func GetUserIDFromContextAsInt64(ctx context.Context) (int64, error) {
 	userID := GetUserIDFromContext(ctx)
 	if userID == "" {
-		return 0, syserr.New(syserr.UnauthorizedCode, "user not authenticated")
+		return 0, syserr.NewPublic(syserr.UnauthorizedCode, "user not authenticated")
 	}
 	userIDInt64, err := strconv.ParseInt(userID, 10, 64)
 	if err != nil {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/duongptryu/gox/shutdown"
 	"github.com/jmoiron/sqlx"
 )
 
@@ -22,6 +23,9 @@ type Config struct {
 	MaxIdleTime  time.Duration
 }
 
+// NewConnection connects to the database and registers the connection as the "database" shutdown hook,
+// so that it is closed by shutdown.Run once the components using it are stopped. Every call registers
+// its connection and keeps it referenced until shutdown, it is meant for the long-lived connections of a service.
 func NewConnection(cfg *Config) (*sqlx.DB, error) {
 	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
 		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Name, cfg.SSLMode)
@@ -37,5 +41,12 @@ func NewConnection(cfg *Config) (*sqlx.DB, error) {
 	db.SetConnMaxLifetime(cfg.MaxLifetime)
 	db.SetConnMaxIdleTime(cfg.MaxIdleTime)
 
-	return db, db.Ping()
+	if err := db.Ping(); err != nil {
+		return db, err
+	}
+
+	// Closed by the shutdown hooks once the components using it are stopped
+	shutdown.RegisterCloser("database", db)
+
+	return db, nil
 }
@@ -0,0 +1,107 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/duongptryu/gox/syserr"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+)
+
+// Postgres SQLSTATE codes recognized by TranslateError
+const (
+	UniqueViolationCode      = "23505"
+	ForeignKeyViolationCode  = "23503"
+	CheckViolationCode       = "23514"
+	NotNullViolationCode     = "23502"
+	SerializationFailureCode = "40001"
+	DeadlockDetectedCode     = "40P01"
+)
+
+// sqlStateCodes maps Postgres SQLSTATE codes to syserr codes
+var sqlStateCodes = map[string]syserr.Code{
+	UniqueViolationCode:      syserr.ConflictCode,
+	ForeignKeyViolationCode:  syserr.ConflictCode,
+	CheckViolationCode:       syserr.InvalidArgumentCode,
+	NotNullViolationCode:     syserr.InvalidArgumentCode,
+	SerializationFailureCode: syserr.AbortedCode,
+	DeadlockDetectedCode:     syserr.AbortedCode,
+}
+
+// driverError holds the parts of a lib/pq or pgx error used for translation
+type driverError struct {
+	sqlState   string
+	constraint string
+	table      string
+	column     string
+}
+
+// TranslateError wraps a database error into a syserr error with the matching code.
+// sql.ErrNoRows and pgx.ErrNoRows become NotFoundCode, and lib/pq and pgx errors are mapped
+// by SQLSTATE with the constraint, table and column attached as fields.
+// Unrecognized errors keep their code as with syserr.WrapAsIs. A nil error returns nil.
+func TranslateError(err error, message string, fields ...*syserr.Field) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+		return syserr.Wrap(err, syserr.NotFoundCode, message, fields...)
+	}
+
+	driverErr, ok := extractDriverError(err)
+	if !ok {
+		return syserr.WrapAsIs(err, message, fields...)
+	}
+
+	code, ok := sqlStateCodes[driverErr.sqlState]
+	if !ok {
+		code = syserr.InternalCode
+	}
+
+	fields = append(fields, syserr.F("sql_state", driverErr.sqlState))
+	if driverErr.constraint != "" {
+		fields = append(fields, syserr.F("constraint", driverErr.constraint))
+	}
+	if driverErr.table != "" {
+		fields = append(fields, syserr.F("table", driverErr.table))
+	}
+	if driverErr.column != "" {
+		fields = append(fields, syserr.F("column", driverErr.column))
+	}
+
+	return syserr.Wrap(err, code, message, fields...)
+}
+
+// SQLState returns the Postgres SQLSTATE code of a lib/pq or pgx error, or an empty string
+func SQLState(err error) string {
+	driverErr, _ := extractDriverError(err)
+	return driverErr.sqlState
+}
+
+func extractDriverError(err error) (driverError, bool) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return driverError{
+			sqlState:   pgErr.Code,
+			constraint: pgErr.ConstraintName,
+			table:      pgErr.TableName,
+			column:     pgErr.ColumnName,
+		}, true
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return driverError{
+			sqlState:   string(pqErr.Code),
+			constraint: pqErr.Constraint,
+			table:      pqErr.Table,
+			column:     pqErr.Column,
+		}, true
+	}
+
+	return driverError{}, false
+}
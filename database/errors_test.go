@@ -0,0 +1,88 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/duongptryu/gox/syserr"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+)
+
+// TestTranslateError tests the code mapping of driver errors
+func TestTranslateError(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected syserr.Code
+	}{
+		{"sql no rows", sql.ErrNoRows, syserr.NotFoundCode},
+		{"pgx no rows", pgx.ErrNoRows, syserr.NotFoundCode},
+		{"wrapped no rows", fmt.Errorf("get user: %w", sql.ErrNoRows), syserr.NotFoundCode},
+		{"pq unique", &pq.Error{Code: UniqueViolationCode}, syserr.ConflictCode},
+		{"pgx unique", &pgconn.PgError{Code: UniqueViolationCode}, syserr.ConflictCode},
+		{"foreign key", &pgconn.PgError{Code: ForeignKeyViolationCode}, syserr.ConflictCode},
+		{"check", &pq.Error{Code: CheckViolationCode}, syserr.InvalidArgumentCode},
+		{"not null", &pgconn.PgError{Code: NotNullViolationCode}, syserr.InvalidArgumentCode},
+		{"serialization failure", &pq.Error{Code: SerializationFailureCode}, syserr.AbortedCode},
+		{"deadlock", &pgconn.PgError{Code: DeadlockDetectedCode}, syserr.AbortedCode},
+		{"unknown sql state", &pgconn.PgError{Code: "42P01"}, syserr.InternalCode},
+		{"generic", errors.New("connection reset"), syserr.InternalCode},
+		{"syserr", syserr.New(syserr.ForbiddenCode, "denied"), syserr.ForbiddenCode},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := TranslateError(testCase.err, "query failed")
+
+			if actual := syserr.GetCodeFromGenericError(err); actual != testCase.expected {
+				t.Errorf("Expected code %s, got %s", testCase.expected, actual)
+			}
+			if !errors.Is(err, testCase.err) {
+				t.Errorf("Expected translated error to wrap %v", testCase.err)
+			}
+		})
+	}
+
+	if err := TranslateError(nil, "query failed"); err != nil {
+		t.Errorf("Expected nil error, got %v", err)
+	}
+}
+
+// TestTranslateErrorFields tests that constraint, table and column are attached as fields
+func TestTranslateErrorFields(t *testing.T) {
+	driverErrs := []error{
+		&pq.Error{Code: NotNullViolationCode, Constraint: "users_email_key", Table: "users", Column: "email"},
+		&pgconn.PgError{Code: NotNullViolationCode, ConstraintName: "users_email_key", TableName: "users", ColumnName: "email"},
+	}
+
+	for _, driverErr := range driverErrs {
+		err := TranslateError(driverErr, "failed to insert user", syserr.F("user_id", 1))
+
+		fields := map[string]any{}
+		for _, field := range syserr.GetFieldsFromGenericError(err) {
+			fields[field.Key] = field.Value
+		}
+
+		expected := map[string]any{
+			"user_id":    1,
+			"sql_state":  NotNullViolationCode,
+			"constraint": "users_email_key",
+			"table":      "users",
+			"column":     "email",
+		}
+		for key, value := range expected {
+			if fields[key] != value {
+				t.Errorf("%T: expected field %s=%v, got %v", driverErr, key, value, fields[key])
+			}
+		}
+
+		if SQLState(err) != NotNullViolationCode {
+			t.Errorf("%T: expected SQLState %s, got %s", driverErr, NotNullViolationCode, SQLState(err))
+		}
+	}
+}
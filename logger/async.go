@@ -0,0 +1,184 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+const defaultAsyncBufferSize = 1024
+
+// OverflowPolicy selects what the async writer does when its buffer is full
+type OverflowPolicy int
+
+const (
+	// OverflowBlock waits until the buffer has room
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropOldest drops the oldest buffered record
+	OverflowDropOldest
+	// OverflowDropNewest drops the record being written
+	OverflowDropNewest
+)
+
+// AsyncConfig configures the asynchronous writer
+type AsyncConfig struct {
+	// BufferSize is the number of records buffered. Zero uses 1024.
+	BufferSize int
+	Overflow   OverflowPolicy
+}
+
+// AsyncWriter writes records to the underlying writer from a background goroutine,
+// so that slow outputs do not stall callers. Records are buffered in a bounded ring buffer.
+type AsyncWriter struct {
+	output   io.Writer
+	overflow OverflowPolicy
+	dropped  atomic.Uint64
+
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	// changed is signalled when records are taken from the buffer or written
+	changed *sync.Cond
+	buffer  [][]byte
+	head    int
+	size    int
+	writing bool
+	closed  bool
+	done    chan struct{}
+}
+
+// NewAsyncWriter creates an async writer and starts its background goroutine
+func NewAsyncWriter(output io.Writer, config AsyncConfig) *AsyncWriter {
+	if config.BufferSize <= 0 {
+		config.BufferSize = defaultAsyncBufferSize
+	}
+
+	w := &AsyncWriter{
+		output:   output,
+		overflow: config.Overflow,
+		buffer:   make([][]byte, config.BufferSize),
+		done:     make(chan struct{}),
+	}
+	w.notEmpty = sync.NewCond(&w.mu)
+	w.changed = sync.NewCond(&w.mu)
+
+	go w.run()
+
+	return w
+}
+
+// Write buffers a copy of p. It never fails because of a full buffer,
+// records are dropped according to the overflow policy instead.
+func (w *AsyncWriter) Write(p []byte) (int, error) {
+	record := append([]byte(nil), p...)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+
+	if w.size == len(w.buffer) {
+		switch w.overflow {
+		case OverflowDropNewest:
+			w.dropped.Add(1)
+			return len(p), nil
+		case OverflowDropOldest:
+			w.buffer[w.head] = nil
+			w.head = (w.head + 1) % len(w.buffer)
+			w.size--
+			w.dropped.Add(1)
+		default:
+			for w.size == len(w.buffer) && !w.closed {
+				w.changed.Wait()
+			}
+			if w.closed {
+				return 0, os.ErrClosed
+			}
+		}
+	}
+
+	w.buffer[(w.head+w.size)%len(w.buffer)] = record
+	w.size++
+	w.notEmpty.Signal()
+
+	return len(p), nil
+}
+
+// Dropped returns the number of records dropped because the buffer was full
+func (w *AsyncWriter) Dropped() uint64 {
+	return w.dropped.Load()
+}
+
+// Flush waits until the buffered records are written or the context is done
+func (w *AsyncWriter) Flush(ctx context.Context) error {
+	flushed := make(chan struct{})
+
+	go func() {
+		w.mu.Lock()
+		for (w.size > 0 || w.writing) && ctx.Err() == nil {
+			w.changed.Wait()
+		}
+		w.mu.Unlock()
+		close(flushed)
+	}()
+
+	select {
+	case <-flushed:
+		return ctx.Err()
+	case <-ctx.Done():
+		// Wake up the waiting goroutine so that it can exit
+		w.mu.Lock()
+		w.changed.Broadcast()
+		w.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// Close writes the buffered records and stops the background goroutine
+func (w *AsyncWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	w.notEmpty.Broadcast()
+	w.changed.Broadcast()
+	w.mu.Unlock()
+
+	<-w.done
+	return nil
+}
+
+func (w *AsyncWriter) run() {
+	defer close(w.done)
+
+	for {
+		w.mu.Lock()
+		for w.size == 0 && !w.closed {
+			w.notEmpty.Wait()
+		}
+		if w.size == 0 && w.closed {
+			w.mu.Unlock()
+			return
+		}
+
+		record := w.buffer[w.head]
+		w.buffer[w.head] = nil
+		w.head = (w.head + 1) % len(w.buffer)
+		w.size--
+		w.writing = true
+		w.changed.Broadcast()
+		w.mu.Unlock()
+
+		_, _ = w.output.Write(record)
+
+		w.mu.Lock()
+		w.writing = false
+		w.changed.Broadcast()
+		w.mu.Unlock()
+	}
+}
@@ -0,0 +1,164 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockingWriter records writes and blocks them until released
+type blockingWriter struct {
+	mu      sync.Mutex
+	lines   []string
+	started chan struct{}
+	release chan struct{}
+}
+
+func newBlockingWriter() *blockingWriter {
+	return &blockingWriter{started: make(chan struct{}, 16), release: make(chan struct{})}
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	w.started <- struct{}{}
+	<-w.release
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.lines = append(w.lines, string(p))
+	return len(p), nil
+}
+
+func (w *blockingWriter) written() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]string(nil), w.lines...)
+}
+
+// TestAsyncWriterOverflow tests the drop policies when the buffer is full
+func TestAsyncWriterOverflow(t *testing.T) {
+	tests := []struct {
+		name     string
+		overflow OverflowPolicy
+		expected []string
+	}{
+		{name: "drop oldest", overflow: OverflowDropOldest, expected: []string{"0", "3", "4"}},
+		{name: "drop newest", overflow: OverflowDropNewest, expected: []string{"0", "1", "2"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := newBlockingWriter()
+			writer := NewAsyncWriter(output, AsyncConfig{BufferSize: 2, Overflow: test.overflow})
+
+			// The first line is taken by the worker, which blocks on it
+			_, _ = writer.Write([]byte("0"))
+			<-output.started
+
+			for _, line := range []string{"1", "2", "3", "4"} {
+				if n, err := writer.Write([]byte(line)); err != nil || n != 1 {
+					t.Fatalf("Unexpected write result %d, %v", n, err)
+				}
+			}
+
+			if writer.Dropped() != 2 {
+				t.Errorf("Expected 2 dropped lines, got %d", writer.Dropped())
+			}
+
+			close(output.release)
+			if err := writer.Close(); err != nil {
+				t.Fatalf("Unexpected close error: %v", err)
+			}
+
+			if got := output.written(); strings.Join(got, ",") != strings.Join(test.expected, ",") {
+				t.Errorf("Expected %v, got %v", test.expected, got)
+			}
+		})
+	}
+}
+
+// TestAsyncWriterBlock tests that writes wait for room in the buffer
+func TestAsyncWriterBlock(t *testing.T) {
+	output := newBlockingWriter()
+	writer := NewAsyncWriter(output, AsyncConfig{BufferSize: 1})
+
+	_, _ = writer.Write([]byte("0"))
+	<-output.started
+	_, _ = writer.Write([]byte("1"))
+
+	written := make(chan struct{})
+	go func() {
+		_, _ = writer.Write([]byte("2"))
+		close(written)
+	}()
+
+	select {
+	case <-written:
+		t.Fatal("Expected the write to block while the buffer is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(output.release)
+	<-written
+
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Unexpected close error: %v", err)
+	}
+	if got := output.written(); len(got) != 3 || writer.Dropped() != 0 {
+		t.Errorf("Expected 3 lines and none dropped, got %v and %d dropped", got, writer.Dropped())
+	}
+}
+
+// TestAsyncWriterFlush tests that Flush waits for buffered lines and honours the context
+func TestAsyncWriterFlush(t *testing.T) {
+	output := newBlockingWriter()
+	writer := NewAsyncWriter(output, AsyncConfig{})
+	defer writer.Close()
+
+	_, _ = writer.Write([]byte("0"))
+	<-output.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := writer.Flush(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded while the output is blocked, got %v", err)
+	}
+
+	close(output.release)
+	if err := writer.Flush(context.Background()); err != nil {
+		t.Fatalf("Unexpected flush error: %v", err)
+	}
+	if got := output.written(); len(got) != 1 {
+		t.Errorf("Expected the line to be written after flush, got %v", got)
+	}
+}
+
+// TestAsyncLogger tests a logger writing through the async writer
+func TestAsyncLogger(t *testing.T) {
+	var buffer bytes.Buffer
+	log := New(&Config{Output: &buffer, Async: &AsyncConfig{BufferSize: 8}}).Named("orders")
+	ctx := context.Background()
+
+	log.Info(ctx, "Order created")
+	if err := log.Flush(ctx); err != nil {
+		t.Fatalf("Unexpected flush error: %v", err)
+	}
+	if !strings.Contains(buffer.String(), "Order created") {
+		t.Fatalf("Expected the record after flush, got %q", buffer.String())
+	}
+
+	if err := log.Close(ctx); err != nil {
+		t.Fatalf("Unexpected close error: %v", err)
+	}
+	if _, err := log.outputs.asyncs[0].Write([]byte("late")); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("Expected os.ErrClosed after close, got %v", err)
+	}
+	log.Info(ctx, "Dropped after close")
+	if strings.Contains(buffer.String(), "Dropped after close") {
+		t.Error("Expected records logged after close to be discarded")
+	}
+}
@@ -0,0 +1,178 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	colorReset  = "\033[0m"
+	colorGray   = "\033[90m"
+	colorRed    = "\033[31m"
+	colorYellow = "\033[33m"
+	colorBlue   = "\033[34m"
+	colorCyan   = "\033[36m"
+
+	consoleTimeFormat = "15:04:05.000"
+)
+
+// consoleHandler writes colorized, human readable lines such as
+// "15:04:05.000 INF Server started address=:8080"
+type consoleHandler struct {
+	opts   slog.HandlerOptions
+	mu     *sync.Mutex
+	output io.Writer
+	// attrs holds the preformatted attributes added with WithAttrs
+	attrs  string
+	groups []string
+}
+
+func newConsoleHandler(output io.Writer, opts *slog.HandlerOptions) *consoleHandler {
+	handler := &consoleHandler{
+		mu:     &sync.Mutex{},
+		output: output,
+	}
+	if opts != nil {
+		handler.opts = *opts
+	}
+
+	return handler
+}
+
+func (h *consoleHandler) Enabled(_ context.Context, level slog.Level) bool {
+	minLevel := slog.LevelInfo
+	if h.opts.Level != nil {
+		minLevel = h.opts.Level.Level()
+	}
+
+	return level >= minLevel
+}
+
+func (h *consoleHandler) Handle(_ context.Context, record slog.Record) error {
+	var buffer bytes.Buffer
+
+	if !record.Time.IsZero() {
+		buffer.WriteString(colorGray + record.Time.Format(consoleTimeFormat) + colorReset + " ")
+	}
+
+	buffer.WriteString(formatConsoleLevel(record.Level) + " ")
+
+	if h.opts.AddSource && record.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{record.PC}).Next()
+		buffer.WriteString(colorGray + filepath.Base(frame.File) + ":" + strconv.Itoa(frame.Line) + colorReset + " ")
+	}
+
+	buffer.WriteString(record.Message)
+	buffer.WriteString(h.attrs)
+
+	prefix := h.groupPrefix()
+	record.Attrs(func(attr slog.Attr) bool {
+		h.appendAttr(&buffer, prefix, attr)
+		return true
+	})
+
+	buffer.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	_, err := h.output.Write(buffer.Bytes())
+	return err
+}
+
+func (h *consoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var buffer bytes.Buffer
+	buffer.WriteString(h.attrs)
+
+	prefix := h.groupPrefix()
+	for _, attr := range attrs {
+		h.appendAttr(&buffer, prefix, attr)
+	}
+
+	clone := *h
+	clone.attrs = buffer.String()
+	return &clone
+}
+
+func (h *consoleHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	clone := *h
+	clone.groups = append(append([]string(nil), h.groups...), name)
+	return &clone
+}
+
+func (h *consoleHandler) groupPrefix() string {
+	if len(h.groups) == 0 {
+		return ""
+	}
+	return strings.Join(h.groups, ".") + "."
+}
+
+func (h *consoleHandler) appendAttr(buffer *bytes.Buffer, prefix string, attr slog.Attr) {
+	if h.opts.ReplaceAttr != nil && attr.Value.Kind() != slog.KindGroup {
+		attr = h.opts.ReplaceAttr(h.groups, attr)
+	}
+
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if attr.Key != "" {
+			groupPrefix += attr.Key + "."
+		}
+		for _, member := range attr.Value.Group() {
+			h.appendAttr(buffer, groupPrefix, member)
+		}
+		return
+	}
+
+	buffer.WriteString(" " + colorCyan + prefix + attr.Key + "=" + colorReset)
+	buffer.WriteString(formatConsoleValue(attr.Value))
+}
+
+func formatConsoleLevel(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError:
+		return colorRed + "ERR" + colorReset
+	case level >= slog.LevelWarn:
+		return colorYellow + "WRN" + colorReset
+	case level >= slog.LevelInfo:
+		return colorBlue + "INF" + colorReset
+	default:
+		return colorGray + "DBG" + colorReset
+	}
+}
+
+func formatConsoleValue(value slog.Value) string {
+	var text string
+
+	switch value.Kind() {
+	case slog.KindTime:
+		text = value.Time().Format(time.RFC3339Nano)
+	case slog.KindAny:
+		text = fmt.Sprint(value.Any())
+	default:
+		text = value.String()
+	}
+
+	if text == "" || strings.ContainsAny(text, " \t\n\"=") {
+		return strconv.Quote(text)
+	}
+
+	return text
+}
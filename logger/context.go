@@ -0,0 +1,28 @@
+package logger
+
+import "context"
+
+type fieldsContextKey struct{}
+
+// WithFields returns a context carrying the fields, added to every record logged with the context.
+// Fields accumulate across calls.
+func WithFields(ctx context.Context, fields ...*Field) context.Context {
+	if len(fields) == 0 {
+		return ctx
+	}
+
+	existing := getContextFields(ctx)
+
+	merged := make([]*Field, 0, len(existing)+len(fields))
+	merged = append(merged, existing...)
+	merged = append(merged, fields...)
+
+	return context.WithValue(ctx, fieldsContextKey{}, merged)
+}
+
+func getContextFields(ctx context.Context) []*Field {
+	if fields, ok := ctx.Value(fieldsContextKey{}).([]*Field); ok {
+		return fields
+	}
+	return nil
+}
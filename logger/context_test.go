@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"testing/slogtest"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// TestWithFields tests that context fields are added to every record logged with the context
+func TestWithFields(t *testing.T) {
+	t.Parallel()
+
+	var buffer bytes.Buffer
+	log := New(&Config{Output: &buffer})
+
+	parent := WithFields(context.Background(), F("tenant_id", "acme"))
+	ctx := WithFields(parent, F("order_id", 42), F("api_token", "secret"))
+
+	log.Info(ctx, "Order created")
+	log.LogError(ctx, syserr.New(syserr.InternalCode, "payment failed"))
+	log.Info(parent, "Tenant only")
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines, got %d", len(lines))
+	}
+
+	for _, line := range lines[:2] {
+		record := decodeRecord(t, []byte(line))
+		if record["tenant_id"] != "acme" || record["order_id"] != float64(42) || record["api_token"] != "[REDACTED]" {
+			t.Errorf("Expected context fields in %v", record)
+		}
+	}
+
+	if record := decodeRecord(t, []byte(lines[2])); record["order_id"] != nil || record["tenant_id"] != "acme" {
+		t.Errorf("Expected only the parent fields in %v", record)
+	}
+}
+
+// TestWithFieldsAndGroups tests that context fields stay at the top level of records logged in a group
+func TestWithFieldsAndGroups(t *testing.T) {
+	t.Parallel()
+
+	var buffer bytes.Buffer
+	log := New(&Config{Output: &buffer}).Named("orders")
+
+	ctx := WithFields(context.Background(), F("tenant_id", "acme"))
+	slog.New(log.Handler()).With("service", "orders").WithGroup("order").With("id", 42).
+		WithGroup("payment").InfoContext(ctx, "Payment captured", "amount", 10)
+
+	record := decodeRecord(t, buffer.Bytes())
+	if record["tenant_id"] != "acme" || record["service"] != "orders" || record["logger"] != "orders" {
+		t.Errorf("Expected context fields, attrs and logger name at the top level in %v", record)
+	}
+
+	order, ok := record["order"].(map[string]any)
+	if !ok || order["id"] != float64(42) {
+		t.Fatalf("Expected the order group with its id in %v", record)
+	}
+	if payment, ok := order["payment"].(map[string]any); !ok || payment["amount"] != float64(10) || payment["tenant_id"] != nil {
+		t.Errorf("Expected only the record attrs in the payment group, got %v", order["payment"])
+	}
+}
+
+// TestContextHandlerConformance tests the handler against the slog handler test suite
+func TestContextHandlerConformance(t *testing.T) {
+	var buffer bytes.Buffer
+	log := New(&Config{Output: &buffer})
+
+	err := slogtest.TestHandler(log.Handler(), func() []map[string]any {
+		var records []map[string]any
+		for _, line := range bytes.Split(bytes.TrimSpace(buffer.Bytes()), []byte("\n")) {
+			records = append(records, decodeRecord(t, line))
+		}
+		return records
+	})
+	if err != nil {
+		t.Errorf("Expected the handler to follow the slog rules, got %v", err)
+	}
+}
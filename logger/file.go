@@ -0,0 +1,416 @@
+package logger
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	backupTimeFormat = "2006-01-02T15-04-05.000"
+	compressSuffix   = ".gz"
+	defaultFileMode  = 0o644
+	defaultDirMode   = 0o755
+)
+
+// FileConfig configures a rotating log file
+type FileConfig struct {
+	// Path is the file written to, rotated files are kept next to it as name-<time>.ext
+	Path string
+	// MaxSize rotates the file before it grows beyond this many bytes, zero disables size rotation
+	MaxSize int64
+	// RotateEvery rotates the file at every multiple of this duration (e.g. 24h), zero disables time rotation
+	RotateEvery time.Duration
+	// MaxBackups is the number of rotated files kept, zero keeps all of them
+	MaxBackups int
+	// MaxAge removes rotated files older than this duration, zero keeps them regardless of age
+	MaxAge time.Duration
+	// Compress gzips rotated files
+	Compress bool
+	// ReopenOnSIGHUP reopens the file on SIGHUP, for files rotated by an external tool such as logrotate
+	ReopenOnSIGHUP bool
+}
+
+// FileWriter writes logs to a file, rotating it by size and time.
+// The file is opened on the first write, so that a writer can be configured before the directory exists.
+type FileWriter struct {
+	config FileConfig
+	now    func() time.Time
+
+	mu           sync.Mutex
+	file         *os.File
+	size         int64
+	nextRotation time.Time
+
+	millCh  chan struct{}
+	stopped chan struct{}
+	signals chan os.Signal
+	closed  bool
+}
+
+// NewFileWriter creates a rotating file writer
+func NewFileWriter(config FileConfig) *FileWriter {
+	w := &FileWriter{
+		config:  config,
+		now:     time.Now,
+		millCh:  make(chan struct{}, 1),
+		stopped: make(chan struct{}),
+	}
+
+	if config.ReopenOnSIGHUP {
+		w.signals = make(chan os.Signal, 1)
+		signal.Notify(w.signals, syscall.SIGHUP)
+	}
+
+	go w.run()
+
+	return w
+}
+
+func (w *FileWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+
+	if w.file == nil {
+		if err := w.openExisting(); err != nil {
+			return 0, err
+		}
+	}
+
+	if w.shouldRotate(int64(len(p))) {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+
+	return n, err
+}
+
+// Rotate closes the current file, renames it with the rotation time and opens a new one
+func (w *FileWriter) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return os.ErrClosed
+	}
+
+	return w.rotate()
+}
+
+// Reopen closes the file, the next write opens the file at the configured path again
+func (w *FileWriter) Reopen() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.closeFile()
+}
+
+// Close closes the file and waits for the rotated files to be compressed and removed
+func (w *FileWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	err := w.closeFile()
+	w.mu.Unlock()
+
+	if w.signals != nil {
+		signal.Stop(w.signals)
+	}
+	close(w.millCh)
+	<-w.stopped
+
+	return err
+}
+
+func (w *FileWriter) shouldRotate(size int64) bool {
+	// A record larger than MaxSize is written to a file of its own rather than rejected
+	if w.config.MaxSize > 0 && w.size > 0 && w.size+size > w.config.MaxSize {
+		return true
+	}
+
+	return w.config.RotateEvery > 0 && !w.now().Before(w.nextRotation)
+}
+
+// openExisting opens the file in append mode, the time rotation is counted from its last modification
+func (w *FileWriter) openExisting() error {
+	if err := os.MkdirAll(filepath.Dir(w.config.Path), defaultDirMode); err != nil {
+		return fmt.Errorf("create log directory: %w", err)
+	}
+
+	file, err := os.OpenFile(w.config.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, defaultFileMode)
+	if err != nil {
+		return fmt.Errorf("open log file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("stat log file: %w", err)
+	}
+
+	openedAt := w.now()
+	if info.Size() > 0 {
+		openedAt = info.ModTime()
+	}
+
+	w.file = file
+	w.size = info.Size()
+	w.scheduleRotation(openedAt)
+
+	return nil
+}
+
+func (w *FileWriter) rotate() error {
+	if err := w.closeFile(); err != nil {
+		return err
+	}
+
+	now := w.now()
+	if _, err := os.Stat(w.config.Path); err == nil {
+		if err := os.Rename(w.config.Path, w.backupName(now)); err != nil {
+			return fmt.Errorf("rename log file: %w", err)
+		}
+	}
+
+	file, err := os.OpenFile(w.config.Path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, defaultFileMode)
+	if err != nil {
+		return fmt.Errorf("open log file: %w", err)
+	}
+
+	w.file = file
+	w.size = 0
+	w.scheduleRotation(now)
+
+	// Compression and clean up happen in the background
+	select {
+	case w.millCh <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+func (w *FileWriter) scheduleRotation(from time.Time) {
+	if w.config.RotateEvery > 0 {
+		w.nextRotation = from.Truncate(w.config.RotateEvery).Add(w.config.RotateEvery)
+	}
+}
+
+func (w *FileWriter) closeFile() error {
+	if w.file == nil {
+		return nil
+	}
+
+	err := w.file.Close()
+	w.file = nil
+
+	return err
+}
+
+// backupName returns the name of the file rotated at t, e.g. app-2024-01-01T00-00-00.000.log
+// backupName returns the name of a backup rotated at t. A counter is appended when a backup of
+// the same millisecond exists, e.g. "app-2024-01-01T00-00-00.000-1.log", so that it is not overwritten.
+func (w *FileWriter) backupName(t time.Time) string {
+	prefix, ext := w.backupPrefixAndExt()
+	base := filepath.Join(filepath.Dir(w.config.Path), prefix+t.UTC().Format(backupTimeFormat))
+
+	name := base + ext
+	for counter := 1; fileExists(name) || fileExists(name+compressSuffix); counter++ {
+		name = fmt.Sprintf("%s-%d%s", base, counter, ext)
+	}
+
+	return name
+}
+
+func fileExists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+func (w *FileWriter) backupPrefixAndExt() (string, string) {
+	name := filepath.Base(w.config.Path)
+	ext := filepath.Ext(name)
+	return strings.TrimSuffix(name, ext) + "-", ext
+}
+
+// run compresses and removes rotated files, and reopens the file on SIGHUP
+func (w *FileWriter) run() {
+	defer close(w.stopped)
+
+	for {
+		select {
+		case _, ok := <-w.millCh:
+			if !ok {
+				return
+			}
+			_ = w.mill()
+		case <-w.signals:
+			_ = w.Reopen()
+		}
+	}
+}
+
+type backupFile struct {
+	path      string
+	rotatedAt time.Time
+	// counter orders the backups rotated in the same millisecond
+	counter    int
+	compressed bool
+}
+
+// mill compresses the rotated files and removes those beyond MaxBackups or older than MaxAge
+func (w *FileWriter) mill() error {
+	if !w.config.Compress && w.config.MaxBackups == 0 && w.config.MaxAge == 0 {
+		return nil
+	}
+
+	backups, err := w.backups()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	var kept []backupFile
+	for index, backup := range backups {
+		expired := w.config.MaxAge > 0 && w.now().Sub(backup.rotatedAt) > w.config.MaxAge
+		if (w.config.MaxBackups > 0 && index >= w.config.MaxBackups) || expired {
+			if err := os.Remove(backup.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		kept = append(kept, backup)
+	}
+
+	if w.config.Compress {
+		for _, backup := range kept {
+			if !backup.compressed {
+				if err := compressFile(backup.path); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// backups returns the rotated files, newest first
+func (w *FileWriter) backups() ([]backupFile, error) {
+	dir := filepath.Dir(w.config.Path)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix, ext := w.backupPrefixAndExt()
+
+	var backups []backupFile
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		name := entry.Name()
+		compressed := strings.HasSuffix(name, ext+compressSuffix)
+		timestamp := strings.TrimPrefix(strings.TrimSuffix(strings.TrimSuffix(name, compressSuffix), ext), prefix)
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		rotatedAt, counter, ok := parseBackupTimestamp(timestamp)
+		if !ok {
+			continue
+		}
+
+		backups = append(backups, backupFile{
+			path:       filepath.Join(dir, name),
+			rotatedAt:  rotatedAt,
+			counter:    counter,
+			compressed: compressed,
+		})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		if backups[i].rotatedAt.Equal(backups[j].rotatedAt) {
+			return backups[i].counter > backups[j].counter
+		}
+		return backups[i].rotatedAt.After(backups[j].rotatedAt)
+	})
+
+	return backups, nil
+}
+
+// parseBackupTimestamp parses the rotation time and the optional counter of a backup name
+func parseBackupTimestamp(timestamp string) (time.Time, int, bool) {
+	if len(timestamp) < len(backupTimeFormat) {
+		return time.Time{}, 0, false
+	}
+
+	rotatedAt, err := time.Parse(backupTimeFormat, timestamp[:len(backupTimeFormat)])
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+
+	suffix := timestamp[len(backupTimeFormat):]
+	if suffix == "" {
+		return rotatedAt, 0, true
+	}
+
+	counter, err := strconv.Atoi(strings.TrimPrefix(suffix, "-"))
+	if err != nil || !strings.HasPrefix(suffix, "-") || counter <= 0 {
+		return time.Time{}, 0, false
+	}
+
+	return rotatedAt, counter, true
+}
+
+// compressFile gzips the file to path.gz and removes it
+func compressFile(path string) error {
+	source, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	target, err := os.OpenFile(path+compressSuffix, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, defaultFileMode)
+	if err != nil {
+		return err
+	}
+
+	writer := gzip.NewWriter(target)
+	if _, err := io.Copy(writer, source); err != nil {
+		_ = target.Close()
+		_ = os.Remove(path + compressSuffix)
+		return err
+	}
+
+	if err := errors.Join(writer.Close(), target.Close()); err != nil {
+		_ = os.Remove(path + compressSuffix)
+		return err
+	}
+
+	_ = source.Close()
+	return os.Remove(path)
+}
@@ -0,0 +1,200 @@
+package logger
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readDir(t *testing.T, dir string) []string {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to read directory: %v", err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// TestFileWriterSizeRotation tests size rotation, backup limits and compression
+func TestFileWriterSizeRotation(t *testing.T) {
+	dir := t.TempDir()
+	writer := NewFileWriter(FileConfig{
+		Path:       filepath.Join(dir, "logs", "app.log"),
+		MaxSize:    10,
+		MaxBackups: 2,
+		Compress:   true,
+	})
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	writer.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+
+	for _, line := range []string{"line-1\n", "line-2\n", "line-3\n", "line-4\n"} {
+		if _, err := writer.Write([]byte(line)); err != nil {
+			t.Fatalf("Unexpected write error: %v", err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Unexpected close error: %v", err)
+	}
+
+	names := readDir(t, filepath.Join(dir, "logs"))
+	expected := []string{
+		"app-2024-01-01T00-00-03.000.log.gz",
+		"app-2024-01-01T00-00-04.000.log.gz",
+		"app.log",
+	}
+	if strings.Join(names, ",") != strings.Join(expected, ",") {
+		t.Fatalf("Expected files %v, got %v", expected, names)
+	}
+
+	current, _ := os.ReadFile(filepath.Join(dir, "logs", "app.log"))
+	if string(current) != "line-4\n" {
+		t.Errorf("Expected the last line in the current file, got %q", current)
+	}
+
+	file, err := os.Open(filepath.Join(dir, "logs", expected[1]))
+	if err != nil {
+		t.Fatalf("Failed to open backup: %v", err)
+	}
+	defer file.Close()
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("Failed to read gzip: %v", err)
+	}
+	content, _ := io.ReadAll(reader)
+	if string(content) != "line-3\n" {
+		t.Errorf("Expected line-3 in the newest backup, got %q", content)
+	}
+}
+
+// TestFileWriterBackupCollision tests that backups rotated in the same millisecond are not overwritten
+func TestFileWriterBackupCollision(t *testing.T) {
+	dir := t.TempDir()
+	writer := NewFileWriter(FileConfig{Path: filepath.Join(dir, "app.log"), MaxSize: 10, MaxBackups: 2})
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	writer.now = func() time.Time { return now }
+
+	for _, line := range []string{"line-1\n", "line-2\n", "line-3\n", "line-4\n"} {
+		if _, err := writer.Write([]byte(line)); err != nil {
+			t.Fatalf("Unexpected write error: %v", err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Unexpected close error: %v", err)
+	}
+
+	names := readDir(t, dir)
+	expected := []string{
+		"app-2024-01-01T00-00-00.000-1.log",
+		"app-2024-01-01T00-00-00.000-2.log",
+		"app.log",
+	}
+	if strings.Join(names, ",") != strings.Join(expected, ",") {
+		t.Fatalf("Expected files %v, got %v", expected, names)
+	}
+
+	for index, content := range []string{"line-2\n", "line-3\n"} {
+		backup, _ := os.ReadFile(filepath.Join(dir, expected[index]))
+		if string(backup) != content {
+			t.Errorf("Expected %q in %s, got %q", content, expected[index], backup)
+		}
+	}
+}
+
+// TestFileWriterTimeRotation tests rotation at interval boundaries and removal of old backups
+func TestFileWriterTimeRotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	// A backup older than MaxAge is removed on the next rotation
+	if err := os.WriteFile(filepath.Join(dir, "app-2023-12-01T00-00-00.000.log"), []byte("old\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	writer := NewFileWriter(FileConfig{Path: path, RotateEvery: time.Hour, MaxAge: 24 * time.Hour})
+	now := time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC)
+	writer.now = func() time.Time { return now }
+
+	_, _ = writer.Write([]byte("first\n"))
+	now = now.Add(20 * time.Minute)
+	_, _ = writer.Write([]byte("same hour\n"))
+	now = now.Add(20 * time.Minute)
+	_, _ = writer.Write([]byte("next hour\n"))
+
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Unexpected close error: %v", err)
+	}
+
+	names := readDir(t, dir)
+	if strings.Join(names, ",") != "app-2024-01-01T11-10-00.000.log,app.log" {
+		t.Fatalf("Unexpected files %v", names)
+	}
+
+	rotated, _ := os.ReadFile(filepath.Join(dir, names[0]))
+	current, _ := os.ReadFile(path)
+	if string(rotated) != "first\nsame hour\n" || string(current) != "next hour\n" {
+		t.Errorf("Unexpected contents %q and %q", rotated, current)
+	}
+}
+
+// TestFileWriterReopen tests that the file is reopened after an external rename
+func TestFileWriterReopen(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	writer := NewFileWriter(FileConfig{Path: path})
+	defer writer.Close()
+
+	_, _ = writer.Write([]byte("before\n"))
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writer.Reopen(); err != nil {
+		t.Fatalf("Unexpected reopen error: %v", err)
+	}
+	_, _ = writer.Write([]byte("after\n"))
+
+	current, _ := os.ReadFile(path)
+	if string(current) != "after\n" {
+		t.Errorf("Expected the new file to be written, got %q", current)
+	}
+}
+
+// TestLoggerFileOutput tests a logger writing to a file alongside another output
+func TestLoggerFileOutput(t *testing.T) {
+	var buffer strings.Builder
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	log := New(&Config{Output: &buffer, File: &FileConfig{Path: path}})
+	log.Info(context.Background(), "Order created")
+
+	if err := log.Close(context.Background()); err != nil {
+		t.Fatalf("Unexpected close error: %v", err)
+	}
+
+	content, _ := os.ReadFile(path)
+	if !strings.Contains(string(content), "Order created") || !strings.Contains(buffer.String(), "Order created") {
+		t.Errorf("Expected the record in both outputs, got %q and %q", content, buffer.String())
+	}
+}
@@ -0,0 +1,161 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"slices"
+
+	"github.com/duongptryu/gox/redact"
+)
+
+// Format selects how log records are written
+type Format int
+
+const (
+	// FormatJSON writes one JSON object per line
+	FormatJSON Format = iota
+	// FormatLogfmt writes key=value pairs, one record per line
+	FormatLogfmt
+	// FormatConsole writes colorized, human readable lines for local development
+	FormatConsole
+)
+
+// newFormatHandler creates the handler writing records in the given format
+func newFormatHandler(format Format, output io.Writer, opts *slog.HandlerOptions) slog.Handler {
+	switch format {
+	case FormatLogfmt:
+		return slog.NewTextHandler(output, opts)
+	case FormatConsole:
+		return newConsoleHandler(output, opts)
+	default:
+		return slog.NewJSONHandler(output, opts)
+	}
+}
+
+// contextHandler filters records by the level of its logger name, adds the context fields
+// to every record and redacts attributes before passing them to the wrapped handler,
+// whatever its format
+type contextHandler struct {
+	handler slog.Handler
+	name    string
+	levels  *levelRegistry
+	// policy is the redact policy of the logger, nil uses the current global policy
+	policy *redact.Policy
+	trace  TraceConfig
+	// groups are opened here rather than in the wrapped handler,
+	// so that the context fields stay at the top level of the record
+	groups []handlerGroup
+}
+
+// handlerGroup is a group opened by WithGroup and the attributes added to it
+type handlerGroup struct {
+	name  string
+	attrs []slog.Attr
+}
+
+func newContextHandler(handler slog.Handler, levels *levelRegistry, policy *redact.Policy) *contextHandler {
+	return &contextHandler{handler: handler, levels: levels, policy: policy}
+}
+
+func (h *contextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.levels.enabled(h.name, level) && h.handler.Enabled(ctx, level)
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	result := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+	if h.name != "" {
+		result.AddAttrs(slog.String("logger", h.name))
+	}
+
+	attrs := make([]slog.Attr, 0, record.NumAttrs())
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs = append(attrs, redactAttr(h.redactPolicy(), attr))
+		return true
+	})
+
+	// Nest the attributes in the open groups, from the innermost one
+	for index := len(h.groups) - 1; index >= 0; index-- {
+		group := h.groups[index]
+		attrs = []slog.Attr{{Key: group.name, Value: slog.GroupValue(slices.Concat(group.attrs, attrs)...)}}
+	}
+	result.AddAttrs(attrs...)
+
+	for _, field := range extractContextFields(ctx, h.trace, nil) {
+		result.AddAttrs(redactAttr(h.redactPolicy(), slog.Any(field.key, field.value)))
+	}
+
+	return h.handler.Handle(ctx, result)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for index, attr := range attrs {
+		redacted[index] = redactAttr(h.redactPolicy(), attr)
+	}
+
+	clone := *h
+	if len(h.groups) == 0 {
+		clone.handler = h.handler.WithAttrs(redacted)
+		return &clone
+	}
+
+	clone.groups = slices.Clone(h.groups)
+	last := &clone.groups[len(clone.groups)-1]
+	last.attrs = slices.Concat(last.attrs, redacted)
+	return &clone
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	clone := *h
+	clone.groups = append(slices.Clip(h.groups), handlerGroup{name: name})
+	return &clone
+}
+
+// named returns a handler filtering records with the level of the named logger.
+// The name is appended to the name of the handler, so that nested loggers emit a single "logger" attribute.
+func (h *contextHandler) named(name string) *contextHandler {
+	clone := *h
+	if h.name == "" {
+		clone.name = name
+	} else {
+		clone.name = h.name + "." + name
+	}
+	return &clone
+}
+
+func (h *contextHandler) redactPolicy() *redact.Policy {
+	if h.policy != nil {
+		return h.policy
+	}
+	return redact.GetPolicy()
+}
+
+// redactAttr applies the redact policy to the attribute and the members of groups
+func redactAttr(policy *redact.Policy, attr slog.Attr) slog.Attr {
+	// Errors are passed as they are so that handlers can inspect them,
+	// syserr errors redact their fields in LogValue
+	if attr.Value.Kind() == slog.KindLogValuer {
+		if _, ok := attr.Value.Any().(error); ok {
+			return attr
+		}
+	}
+
+	attr.Value = attr.Value.Resolve()
+
+	if attr.Value.Kind() != slog.KindGroup {
+		return policy.ReplaceAttr(nil, attr)
+	}
+
+	members := attr.Value.Group()
+	redacted := make([]slog.Attr, len(members))
+	for index, member := range members {
+		redacted[index] = redactAttr(policy, member)
+	}
+
+	return slog.Attr{Key: attr.Key, Value: slog.GroupValue(redacted...)}
+}
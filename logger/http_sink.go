@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const defaultHTTPTimeout = 10 * time.Second
+
+// HTTPConfig configures a sink posting records to an HTTP JSON collector
+type HTTPConfig struct {
+	// URL receives batches as a JSON array of records
+	URL string
+	// Headers are added to every request, e.g. an API key
+	Headers map[string]string
+	// Client sends the requests, a client with a 10s timeout by default
+	Client *http.Client
+	// Batching controls batching, retries and spilling
+	Batching NetworkConfig
+}
+
+// NewHTTPHandler creates a handler posting records to an HTTP collector in batches.
+// Server errors, 429 responses and network errors are retried, other failed responses drop the batch.
+func NewHTTPHandler(config HTTPConfig, opts *slog.HandlerOptions) *NetworkHandler {
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: defaultHTTPTimeout}
+	}
+
+	frame := func(_ slog.Record, message []byte) []byte {
+		return append([]byte(nil), message...)
+	}
+
+	return newNetworkHandler(config.Batching, &httpTransport{config: config}, opts, frame)
+}
+
+type httpTransport struct {
+	config HTTPConfig
+}
+
+func (t *httpTransport) send(ctx context.Context, batch [][]byte) error {
+	body := bytes.NewBuffer(make([]byte, 0, 2+len(batch)*256))
+	body.WriteByte('[')
+	for index, entry := range batch {
+		if index > 0 {
+			body.WriteByte(',')
+		}
+		body.Write(entry)
+	}
+	body.WriteByte(']')
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, t.config.URL, body)
+	if err != nil {
+		return &permanentError{err: err}
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	for key, value := range t.config.Headers {
+		request.Header.Set(key, value)
+	}
+
+	response, err := t.config.Client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return nil
+	}
+
+	err = fmt.Errorf("log collector responded with status %d", response.StatusCode)
+	if response.StatusCode >= 500 || response.StatusCode == http.StatusTooManyRequests {
+		return err
+	}
+	return &permanentError{err: err}
+}
+
+func (t *httpTransport) close() error {
+	return nil
+}
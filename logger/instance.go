@@ -0,0 +1,240 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"github.com/duongptryu/gox/redact"
+	"github.com/duongptryu/gox/syserr"
+)
+
+// Logger is a structured, context-aware logger.
+// Context fields are added and attributes redacted for every record.
+type Logger struct {
+	handler *contextHandler
+	outputs *outputs
+}
+
+// outputs holds the buffered parts of a logger, shared by its children
+type outputs struct {
+	// flushers are flushed in order, the sampling handler before the sinks it logs to
+	flushers []flusher
+	asyncs   []*AsyncWriter
+	// handlers are the sink handlers that buffer records themselves
+	handlers []flusher
+	// closers are the outputs opened by the logger, closed by Close in order,
+	// the sampling handler before the files it logs to
+	closers []io.Closer
+}
+
+type flusher interface {
+	Flush(ctx context.Context) error
+}
+
+type loggerContextKey struct{}
+
+var defaultLogger atomic.Pointer[Logger]
+
+func init() {
+	defaultLogger.Store(newLogger(&Config{Level: slog.LevelInfo, Output: os.Stdout}, levels, nil))
+}
+
+// New creates a logger independent from the default one, with its own level.
+// Unlike Init, it does not change the global redact policy or level.
+func New(cfg *Config) *Logger {
+	if cfg == nil {
+		cfg = &Config{Level: slog.LevelInfo, Output: os.Stdout}
+	}
+
+	return newLogger(cfg, newLevelRegistry(cfg.Level), cfg.Redaction)
+}
+
+func newLogger(cfg *Config, levels *levelRegistry, policy *redact.Policy) *Logger {
+	outputs := &outputs{}
+
+	sinks := cfg.Sinks
+	if len(sinks) == 0 {
+		sinks = []Sink{{Format: cfg.Format, Output: cfg.Output, File: cfg.File, Async: cfg.Async, Handler: cfg.Handler}}
+	}
+
+	opts := &slog.HandlerOptions{
+		Level:       &levels.minimum,
+		AddSource:   cfg.AddSource,
+		ReplaceAttr: cfg.ReplaceAttr,
+	}
+
+	handlers := make([]slog.Handler, len(sinks))
+	for index, sink := range sinks {
+		handlers[index] = newSinkHandler(sink, opts, outputs)
+	}
+	handler := newFanoutHandler(handlers)
+
+	if cfg.Sampling != nil {
+		sampling := newSamplingHandler(handler, *cfg.Sampling)
+		outputs.flushers = append(outputs.flushers, sampling)
+		outputs.closers = append([]io.Closer{sampling}, outputs.closers...)
+		handler = sampling
+	}
+
+	for _, async := range outputs.asyncs {
+		outputs.flushers = append(outputs.flushers, async)
+	}
+	outputs.flushers = append(outputs.flushers, outputs.handlers...)
+
+	root := newContextHandler(handler, levels, policy)
+	root.trace = cfg.Trace
+
+	return &Logger{handler: root, outputs: outputs}
+}
+
+// Default returns the default logger, it is never nil
+func Default() *Logger {
+	return defaultLogger.Load()
+}
+
+// SetDefault replaces the default logger used by the package level functions
+func SetDefault(l *Logger) {
+	if l != nil {
+		defaultLogger.Store(l)
+	}
+}
+
+// WithLogger returns a context carrying the logger, used by the package level functions
+func WithLogger(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey{}, l)
+}
+
+// FromContext returns the logger stored in ctx, or the default logger
+func FromContext(ctx context.Context) *Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(loggerContextKey{}).(*Logger); ok && l != nil {
+			return l
+		}
+	}
+
+	return Default()
+}
+
+// With returns a logger adding the fields to every record
+func (l *Logger) With(fields ...*Field) *Logger {
+	if len(fields) == 0 {
+		return l
+	}
+
+	attrs := make([]slog.Attr, len(fields))
+	for index, field := range fields {
+		attrs[index] = slog.Any(field.key, field.value)
+	}
+
+	return &Logger{handler: l.handler.WithAttrs(attrs).(*contextHandler), outputs: l.outputs}
+}
+
+// Named returns a child logger whose level can be overridden with SetNamedLevel.
+// The name of a named logger's child is joined with a dot, e.g. Named("messaging").Named("cqrs") is "messaging.cqrs".
+func (l *Logger) Named(name string) *Logger {
+	return &Logger{handler: l.handler.named(name), outputs: l.outputs}
+}
+
+// Slog returns the logger as a *slog.Logger, e.g. for libraries accepting one
+func (l *Logger) Slog() *slog.Logger {
+	return slog.New(l.handler)
+}
+
+// Flush writes the records buffered by the logger, including the sampling report,
+// and returns when they are written or the context is done
+func (l *Logger) Flush(ctx context.Context) error {
+	var errs []error
+	for _, f := range l.outputs.flushers {
+		if err := f.Flush(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Close flushes the logger, stops its async writers and closes the files it opened.
+// Records logged afterwards are dropped. Config.Output is not closed.
+func (l *Logger) Close(ctx context.Context) error {
+	errs := []error{l.Flush(ctx)}
+
+	for _, async := range l.outputs.asyncs {
+		errs = append(errs, async.Close())
+	}
+
+	for _, closer := range l.outputs.closers {
+		errs = append(errs, closer.Close())
+	}
+
+	return errors.Join(errs...)
+}
+
+// Dropped returns the number of records dropped by the async writers because their buffer was full
+func (l *Logger) Dropped() uint64 {
+	var dropped uint64
+	for _, async := range l.outputs.asyncs {
+		dropped += async.Dropped()
+	}
+	return dropped
+}
+
+// Handler returns the slog handler of the logger
+func (l *Logger) Handler() slog.Handler {
+	return l.handler
+}
+
+func (l *Logger) Debug(ctx context.Context, message string, fields ...*Field) {
+	l.log(ctx, 0, slog.LevelDebug, message, fields)
+}
+
+func (l *Logger) Info(ctx context.Context, message string, fields ...*Field) {
+	l.log(ctx, 0, slog.LevelInfo, message, fields)
+}
+
+func (l *Logger) Warning(ctx context.Context, message string, fields ...*Field) {
+	l.log(ctx, 0, slog.LevelWarn, message, fields)
+}
+
+func (l *Logger) Error(ctx context.Context, message string, fields ...*Field) {
+	l.log(ctx, 0, slog.LevelError, message, fields)
+}
+
+// LogError logs the error with its code, fields and stack trace at the level registered for its code
+func (l *Logger) LogError(ctx context.Context, err error, fields ...*Field) {
+	l.logError(ctx, 0, err, fields)
+}
+
+func (l *Logger) logError(ctx context.Context, skip int, err error, fields []*Field) {
+	code := syserr.GetCodeFromGenericError(err)
+
+	fields = append(fields, convertErrorFieldsToLoggerFields(syserr.GetFieldsFromGenericError(err))...)
+	fields = append(fields, F("stack", syserr.GetStackFormattedFromGenericError(err)), F("code", code))
+
+	l.log(ctx, skip+1, syserr.GetCodeInfo(code).LogLevel, err.Error(), fields)
+}
+
+// log writes a record with the source of the caller skip frames above the exported function
+func (l *Logger) log(ctx context.Context, skip int, level slog.Level, message string, fields []*Field) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if !l.handler.Enabled(ctx, level) {
+		return
+	}
+
+	// Skip runtime.Callers, log and the exported function
+	var pcs [1]uintptr
+	runtime.Callers(3+skip, pcs[:])
+
+	record := slog.NewRecord(time.Now(), level, message, pcs[0])
+	record.Add(convertFields(fields)...)
+
+	_ = l.handler.Handle(ctx, record)
+}
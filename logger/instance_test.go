@@ -0,0 +1,109 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+func decodeRecord(t *testing.T, data []byte) map[string]any {
+	t.Helper()
+
+	var record map[string]any
+	if err := json.Unmarshal(data, &record); err != nil {
+		t.Fatalf("Failed to decode record %s: %v", data, err)
+	}
+	return record
+}
+
+// TestDefaultLoggerIsNeverNil tests that the package functions work before Init
+func TestDefaultLoggerIsNeverNil(t *testing.T) {
+	if Default() == nil || GetLogger() == nil {
+		t.Fatal("Expected a default logger before Init")
+	}
+
+	// A nil context must not panic either
+	var ctx context.Context
+	Info(ctx, "logged with the default logger")
+}
+
+// TestLoggerInstances tests isolated loggers, With and the context logger
+func TestLoggerInstances(t *testing.T) {
+	t.Parallel()
+
+	var buffer bytes.Buffer
+	log := New(&Config{Level: slog.LevelDebug, Output: &buffer, AddSource: true}).With(F("service", "orders"))
+
+	ctx := WithLogger(context.Background(), log)
+	if FromContext(ctx) != log {
+		t.Fatal("Expected FromContext to return the stored logger")
+	}
+
+	Debug(ctx, "Order created", F("order_id", 42))
+
+	record := decodeRecord(t, buffer.Bytes())
+	if record["msg"] != "Order created" || record["service"] != "orders" || record["order_id"] != float64(42) {
+		t.Errorf("Unexpected record %v", record)
+	}
+
+	source, _ := record["source"].(map[string]any)
+	if file, _ := source["file"].(string); !strings.HasSuffix(file, "instance_test.go") {
+		t.Errorf("Expected the source to be the caller, got %v", record["source"])
+	}
+}
+
+// TestLoggerLogError tests LogError on an instance and its source
+func TestLoggerLogError(t *testing.T) {
+	t.Parallel()
+
+	var buffer bytes.Buffer
+	log := New(&Config{Output: &buffer, AddSource: true})
+
+	log.LogError(context.Background(), syserr.New(syserr.NotFoundCode, "no rows", syserr.F("user_id", 7)))
+
+	record := decodeRecord(t, buffer.Bytes())
+	if record["level"] != "WARN" || record["code"] != "not_found" || record["user_id"] != float64(7) {
+		t.Errorf("Unexpected record %v", record)
+	}
+
+	source, _ := record["source"].(map[string]any)
+	if function, _ := source["function"].(string); !strings.HasSuffix(function, "TestLoggerLogError") {
+		t.Errorf("Expected the source to be the caller, got %v", record["source"])
+	}
+}
+
+// TestLoggerNamed tests that instance loggers have their own levels
+func TestLoggerNamed(t *testing.T) {
+	t.Parallel()
+
+	var buffer bytes.Buffer
+	log := New(&Config{Level: slog.LevelWarn, Output: &buffer})
+
+	log.Named("messaging").Info(context.Background(), "filtered")
+	log.Named("messaging").Warning(context.Background(), "kept")
+
+	output := buffer.String()
+	if strings.Contains(output, "filtered") || !strings.Contains(output, `"logger":"messaging"`) {
+		t.Errorf("Unexpected output %s", output)
+	}
+}
+
+// TestLoggerNamedNested tests that the names of nested loggers are joined into a single attribute
+func TestLoggerNamedNested(t *testing.T) {
+	t.Parallel()
+
+	var buffer bytes.Buffer
+	log := New(&Config{Output: &buffer})
+
+	log.Named("messaging").Named("cqrs").Info(context.Background(), "Command handled")
+
+	output := buffer.String()
+	if strings.Count(output, `"logger"`) != 1 || !strings.Contains(output, `"logger":"messaging.cqrs"`) {
+		t.Errorf("Expected a single logger attribute with the joined name, got %s", output)
+	}
+}
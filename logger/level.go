@@ -0,0 +1,185 @@
+package logger
+
+import (
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// levelRegistry holds the global level and the per-name level overrides of named loggers.
+// Names are hierarchical: "messaging.cqrs" falls back to the level of "messaging".
+type levelRegistry struct {
+	global slog.LevelVar
+	// minimum is the lowest enabled level, used by the built-in handlers so that
+	// records of named loggers with a lower level reach the name filter
+	minimum slog.LevelVar
+
+	mu      sync.RWMutex
+	named   map[string]slog.Level
+	reverts map[string]*pendingRevert
+}
+
+// pendingRevert restores the level of a name when its timer fires
+type pendingRevert struct {
+	timer       *time.Timer
+	level       slog.Level
+	hadOverride bool
+}
+
+// levels is the registry of the default logger, controlled by SetLevel, SetNamedLevel and LevelHandler
+var levels = newLevelRegistry(slog.LevelInfo)
+
+func newLevelRegistry(level slog.Level) *levelRegistry {
+	registry := &levelRegistry{
+		named:   map[string]slog.Level{},
+		reverts: map[string]*pendingRevert{},
+	}
+	registry.global.Set(level)
+	registry.minimum.Set(level)
+
+	return registry
+}
+
+// SetLevel sets the global log level
+func SetLevel(level slog.Level) {
+	levels.set("", level, 0)
+}
+
+// GetLevel returns the global log level
+func GetLevel() slog.Level {
+	return levels.global.Level()
+}
+
+// SetNamedLevel overrides the level of the named logger and its children
+func SetNamedLevel(name string, level slog.Level) {
+	levels.set(name, level, 0)
+}
+
+// ResetNamedLevel removes the level override of the named logger
+func ResetNamedLevel(name string) {
+	levels.reset(name)
+}
+
+// Levels returns the level overrides of named loggers
+func Levels() map[string]slog.Level {
+	levels.mu.RLock()
+	defer levels.mu.RUnlock()
+
+	result := make(map[string]slog.Level, len(levels.named))
+	for name, level := range levels.named {
+		result[name] = level
+	}
+	return result
+}
+
+// enabled reports whether records at level are enabled for the named logger
+func (r *levelRegistry) enabled(name string, level slog.Level) bool {
+	if level < r.minimum.Level() {
+		return false
+	}
+
+	return level >= r.effective(name)
+}
+
+// effective returns the level of the closest named override, or the global level
+func (r *levelRegistry) effective(name string) slog.Level {
+	if name == "" {
+		return r.global.Level()
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for current := name; current != ""; {
+		if level, ok := r.named[current]; ok {
+			return level
+		}
+
+		index := strings.LastIndex(current, ".")
+		if index < 0 {
+			break
+		}
+		current = current[:index]
+	}
+
+	return r.global.Level()
+}
+
+// set changes the level of name, the global level when empty.
+// With a positive duration the level reverts to its value before the first pending change.
+func (r *levelRegistry) set(name string, level slog.Level, duration time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	previous, hadOverride := r.current(name)
+
+	if pending, ok := r.reverts[name]; ok {
+		pending.timer.Stop()
+		delete(r.reverts, name)
+		previous, hadOverride = pending.level, pending.hadOverride
+	}
+
+	r.apply(name, level, true)
+
+	if duration > 0 {
+		r.reverts[name] = &pendingRevert{
+			timer:       time.AfterFunc(duration, func() { r.revert(name) }),
+			level:       previous,
+			hadOverride: hadOverride,
+		}
+	}
+}
+
+func (r *levelRegistry) reset(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if pending, ok := r.reverts[name]; ok {
+		pending.timer.Stop()
+		delete(r.reverts, name)
+	}
+
+	r.apply(name, 0, false)
+}
+
+func (r *levelRegistry) revert(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pending, ok := r.reverts[name]
+	if !ok {
+		return
+	}
+	delete(r.reverts, name)
+
+	r.apply(name, pending.level, pending.hadOverride)
+}
+
+// current returns the level set for name and whether it is set, must be called with the lock held
+func (r *levelRegistry) current(name string) (slog.Level, bool) {
+	if name == "" {
+		return r.global.Level(), true
+	}
+
+	level, ok := r.named[name]
+	return level, ok
+}
+
+// apply sets or removes the level of name and updates the minimum, must be called with the lock held
+func (r *levelRegistry) apply(name string, level slog.Level, set bool) {
+	switch {
+	case name == "":
+		r.global.Set(level)
+	case set:
+		r.named[name] = level
+	default:
+		delete(r.named, name)
+	}
+
+	minimum := r.global.Level()
+	for _, namedLevel := range r.named {
+		minimum = min(minimum, namedLevel)
+	}
+	r.minimum.Set(minimum)
+}
@@ -0,0 +1,84 @@
+package logger
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// levelState is the representation of the levels returned by LevelHandler
+type levelState struct {
+	Level   slog.Level            `json:"level"`
+	Loggers map[string]slog.Level `json:"loggers"`
+}
+
+// levelChange is the body accepted by LevelHandler to change a level
+type levelChange struct {
+	// Logger is the name of the logger, empty for the global level
+	Logger string      `json:"logger"`
+	Level  *slog.Level `json:"level"`
+	// Duration reverts the change after the given time, e.g. "15m"
+	Duration string `json:"duration"`
+}
+
+// LevelHandler returns an HTTP handler to read and change log levels at runtime.
+//
+//	GET    returns {"level":"INFO","loggers":{"messaging":"DEBUG"}}
+//	PUT    accepts {"logger":"messaging","level":"DEBUG","duration":"15m"}, logger and duration are optional
+//	DELETE ?logger=messaging removes the override of a named logger
+//
+// It can be mounted on gin with gin.WrapH and should be protected by authentication.
+func LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut, http.MethodPost:
+			var change levelChange
+			if err := json.NewDecoder(r.Body).Decode(&change); err != nil {
+				writeLevelError(w, "invalid body: "+err.Error())
+				return
+			}
+
+			if change.Level == nil {
+				writeLevelError(w, "level is required")
+				return
+			}
+
+			var duration time.Duration
+			if change.Duration != "" {
+				var err error
+				if duration, err = time.ParseDuration(change.Duration); err != nil || duration < 0 {
+					writeLevelError(w, "invalid duration: "+change.Duration)
+					return
+				}
+			}
+
+			levels.set(change.Logger, *change.Level, duration)
+		case http.MethodDelete:
+			name := r.URL.Query().Get("logger")
+			if name == "" {
+				writeLevelError(w, "logger is required")
+				return
+			}
+
+			levels.reset(name)
+		default:
+			w.Header().Set("Allow", "GET, PUT, POST, DELETE")
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		writeLevelJSON(w, http.StatusOK, levelState{Level: GetLevel(), Loggers: Levels()})
+	})
+}
+
+func writeLevelError(w http.ResponseWriter, message string) {
+	writeLevelJSON(w, http.StatusBadRequest, map[string]string{"error": message})
+}
+
+func writeLevelJSON(w http.ResponseWriter, statusCode int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,117 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func resetLevels() {
+	for name := range Levels() {
+		ResetNamedLevel(name)
+	}
+	SetLevel(slog.LevelInfo)
+}
+
+// TestNamedLevels tests per-name level overrides and their hierarchy
+func TestNamedLevels(t *testing.T) {
+	defer resetLevels()
+
+	var buffer bytes.Buffer
+	root := newContextHandler(slog.NewJSONHandler(&buffer, &slog.HandlerOptions{Level: &levels.minimum}), levels, nil)
+
+	messaging := slog.New(root.named("messaging"))
+	cqrs := slog.New(root.named("messaging.cqrs"))
+	server := slog.New(root.named("server"))
+
+	SetNamedLevel("messaging", slog.LevelDebug)
+
+	ctx := context.Background()
+	messaging.DebugContext(ctx, "messaging debug")
+	cqrs.DebugContext(ctx, "cqrs debug")
+	server.DebugContext(ctx, "server debug")
+	slog.New(root).DebugContext(ctx, "root debug")
+
+	output := buffer.String()
+	for _, expected := range []string{`"msg":"messaging debug","logger":"messaging"`, `"msg":"cqrs debug","logger":"messaging.cqrs"`} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %s, got %s", expected, output)
+		}
+	}
+	if strings.Contains(output, "server debug") || strings.Contains(output, "root debug") {
+		t.Errorf("Expected debug records of other loggers to be filtered, got %s", output)
+	}
+
+	buffer.Reset()
+	SetLevel(slog.LevelWarn)
+	ResetNamedLevel("messaging")
+	messaging.InfoContext(ctx, "messaging info")
+	if buffer.Len() != 0 {
+		t.Errorf("Expected the global level to apply after reset, got %s", buffer.String())
+	}
+}
+
+// TestLevelRevert tests that temporary changes revert to the original level
+func TestLevelRevert(t *testing.T) {
+	defer resetLevels()
+
+	levels.set("messaging", slog.LevelDebug, 20*time.Millisecond)
+	levels.set("messaging", slog.LevelWarn, 20*time.Millisecond)
+
+	if level := levels.effective("messaging"); level != slog.LevelWarn {
+		t.Errorf("Expected WARN, got %v", level)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(Levels()) > 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if _, ok := Levels()["messaging"]; ok {
+		t.Errorf("Expected the override to be removed after the revert timer")
+	}
+}
+
+// TestLevelHandler tests reading and changing levels over HTTP
+func TestLevelHandler(t *testing.T) {
+	defer resetLevels()
+
+	handler := LevelHandler()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"logger":"messaging","level":"debug","duration":"1h"}`)))
+
+	var state levelState
+	if err := json.Unmarshal(recorder.Body.Bytes(), &state); err != nil {
+		t.Fatalf("Failed to decode response %s: %v", recorder.Body.String(), err)
+	}
+	if recorder.Code != http.StatusOK || state.Loggers["messaging"] != slog.LevelDebug || state.Level != slog.LevelInfo {
+		t.Errorf("Unexpected response %d %s", recorder.Code, recorder.Body.String())
+	}
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"level":"WARN"}`)))
+	if GetLevel() != slog.LevelWarn {
+		t.Errorf("Expected global level WARN, got %v", GetLevel())
+	}
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, "/?logger=messaging", nil))
+	if _, ok := Levels()["messaging"]; ok || recorder.Code != http.StatusOK {
+		t.Errorf("Expected the override to be removed, got %d %s", recorder.Code, recorder.Body.String())
+	}
+
+	for _, body := range []string{`{"level":"LOUD"}`, `{"logger":"messaging"}`, `{"level":"INFO","duration":"soon"}`} {
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, "/", strings.NewReader(body)))
+		if recorder.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %s, got %d", body, recorder.Code)
+		}
+	}
+}
@@ -6,23 +6,47 @@ import (
 	"log/slog"
 	"os"
 	"sync"
+	"time"
 
 	pkgContext "github.com/duongptryu/gox/context"
+	"github.com/duongptryu/gox/redact"
+	"github.com/duongptryu/gox/shutdown"
 	"github.com/duongptryu/gox/syserr"
 )
 
 type Config struct {
-	Level       slog.Level
-	Output      io.Writer
+	// Level is the initial global level, it can be changed at runtime with SetLevel
+	Level slog.Level
+	// Output is where the built-in handlers write, stdout when both Output and File are nil
+	Output io.Writer
+	// File writes to a rotating file, alone or alongside Output
+	File        *FileConfig
 	AddSource   bool
 	ReplaceAttr func(groups []string, a slog.Attr) slog.Attr
+	// Format selects JSON (default), logfmt or colorized console output
+	Format Format
+	// Handler replaces the built-in handlers; Level, Output, AddSource, ReplaceAttr and Format are ignored.
+	// Context fields and redaction are still applied.
+	Handler slog.Handler
+	// Async writes Output from a background goroutine through a bounded buffer, nil writes synchronously.
+	// Call Flush or Close before exiting so that buffered records are not lost.
+	Async *AsyncConfig
+	// Sinks write every record to several destinations, each with its own level, format and filter.
+	// When set, Format, Output, File, Async and Handler are ignored.
+	Sinks []Sink
+	// Sampling limits repeated records of the same level and message, nil disables sampling
+	Sampling *SamplingConfig
+	// Trace names the trace_id and span_id fields added from the active trace
+	Trace TraceConfig
+	// Redaction is the policy applied to log attributes and syserr fields.
+	// Nil keeps the current policy, redact.DefaultPolicy unless set with redact.SetPolicy.
+	Redaction *redact.Policy
 }
 
-var (
-	logger *slog.Logger
-	once   sync.Once
-)
+var once sync.Once
 
+// Init configures the default logger used by the package level functions.
+// Only the first call has an effect; the level can be changed later with SetLevel.
 func Init(cfg *Config) {
 	once.Do(func() {
 		if cfg == nil {
@@ -33,24 +57,29 @@ func Init(cfg *Config) {
 			}
 		}
 
-		opts := &slog.HandlerOptions{
-			Level:       cfg.Level,
-			AddSource:   cfg.AddSource,
-			ReplaceAttr: cfg.ReplaceAttr,
+		if cfg.Redaction != nil {
+			redact.SetPolicy(cfg.Redaction)
 		}
 
-		handler := slog.NewJSONHandler(cfg.Output, opts)
+		levels.set("", cfg.Level, 0)
+
+		// The default logger follows the global policy so that it matches syserr field extraction
+		SetDefault(newLogger(cfg, levels, nil))
 
-		logger = slog.New(handler)
+		// Registered first, the logger is flushed after the other resources are released
+		shutdown.Register("logger", Flush)
 	})
 }
 
-func GetLogger() *slog.Logger {
-	if logger == nil {
-		Init(nil)
-	}
+// Named returns a child of the default logger whose level can be overridden with SetNamedLevel.
+// Records carry the name in the "logger" attribute. Names are hierarchical, separated by dots.
+func Named(name string) *Logger {
+	return Default().Named(name)
+}
 
-	return logger
+// GetLogger returns the default logger as a *slog.Logger
+func GetLogger() *slog.Logger {
+	return Default().Slog()
 }
 
 type Field struct {
@@ -65,37 +94,62 @@ func F(key string, value any) *Field {
 	}
 }
 
+// Warning logs with the logger stored in ctx, or the default logger
 func Warning(ctx context.Context, message string, fields ...*Field) {
-	logger.Warn(message, convertFields(extractContextFields(ctx, fields))...)
+	FromContext(ctx).log(ctx, 0, slog.LevelWarn, message, fields)
 }
 
+// Error logs with the logger stored in ctx, or the default logger
 func Error(ctx context.Context, message string, fields ...*Field) {
-	logger.Error(message, convertFields(extractContextFields(ctx, fields))...)
+	FromContext(ctx).log(ctx, 0, slog.LevelError, message, fields)
 }
 
+// Info logs with the logger stored in ctx, or the default logger
 func Info(ctx context.Context, message string, fields ...*Field) {
-	logger.Info(message, convertFields(extractContextFields(ctx, fields))...)
+	FromContext(ctx).log(ctx, 0, slog.LevelInfo, message, fields)
 }
 
+// Debug logs with the logger stored in ctx, or the default logger
 func Debug(ctx context.Context, message string, fields ...*Field) {
-	logger.Debug(message, convertFields(extractContextFields(ctx, fields))...)
+	FromContext(ctx).log(ctx, 0, slog.LevelDebug, message, fields)
 }
 
+const (
+	// fatalTimeout bounds the shutdown hooks run by Fatal before exiting
+	fatalTimeout = 5 * time.Second
+	// flushTimeout bounds the flush following the shutdown hooks, which may have used up their deadline
+	flushTimeout = 2 * time.Second
+)
+
+// Fatal logs at Error level, runs the shutdown hooks, flushes the logger and exits the process
 func Fatal(ctx context.Context, message string, fields ...*Field) {
-	logger.Error(message, convertFields(extractContextFields(ctx, fields))...)
+	l := FromContext(ctx)
+	l.log(ctx, 0, slog.LevelError, message, fields)
+
+	// The context of the caller may already be canceled
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), fatalTimeout)
+	_ = shutdown.Run(shutdownCtx)
+	cancel()
+
+	flushCtx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+	_ = l.Flush(flushCtx)
+	cancel()
+
 	os.Exit(1)
 }
 
-func LogError(ctx context.Context, err error, fields ...*Field) {
-	code := syserr.GetCodeFromGenericError(err)
-
-	fields = append(fields, convertErrorFieldsToLoggerFields(syserr.GetFieldsFromGenericError(err))...)
-	fields = append(fields, F("stack", syserr.GetStackFormattedFromGenericError(err)), F("code", code))
+// Flush writes the records buffered by the default logger, e.g. during graceful shutdown
+func Flush(ctx context.Context) error {
+	return Default().Flush(ctx)
+}
 
-	Error(ctx, err.Error(), fields...)
+// LogError logs the error with its code, fields and stack trace at the level registered for its code
+func LogError(ctx context.Context, err error, fields ...*Field) {
+	FromContext(ctx).logError(ctx, 0, err, fields)
 }
 
-func extractContextFields(ctx context.Context, fields []*Field) []*Field {
+// extractContextFields appends the request-scoped fields found in ctx, it is applied to every record by the handler
+func extractContextFields(ctx context.Context, trace TraceConfig, fields []*Field) []*Field {
 	if ctx == nil {
 		return fields
 	}
@@ -110,6 +164,8 @@ func extractContextFields(ctx context.Context, fields []*Field) []*Field {
 		fields = append(fields, F("request_id", requestID))
 	}
 
+	fields = extractTraceFields(ctx, trace, fields)
+
 	userID := pkgContext.GetUserIDFromContext(ctx)
 	if userID != "" {
 		fields = append(fields, F("user_id", userID))
@@ -120,6 +176,8 @@ func extractContextFields(ctx context.Context, fields []*Field) []*Field {
 		fields = append(fields, F("user_type", userType))
 	}
 
+	fields = append(fields, getContextFields(ctx)...)
+
 	return fields
 }
 
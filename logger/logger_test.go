@@ -0,0 +1,106 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	pkgContext "github.com/duongptryu/gox/context"
+	"github.com/duongptryu/gox/syserr"
+)
+
+// recordingHandler keeps the records it handles
+type recordingHandler struct {
+	records []slog.Record
+}
+
+func (h *recordingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *recordingHandler) Handle(_ context.Context, record slog.Record) error {
+	h.records = append(h.records, record)
+	return nil
+}
+
+func (h *recordingHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h *recordingHandler) WithGroup(string) slog.Handler { return h }
+
+func recordAttrs(record slog.Record) map[string]slog.Value {
+	attrs := map[string]slog.Value{}
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs[attr.Key] = attr.Value
+		return true
+	})
+	return attrs
+}
+
+// TestContextHandlerCustomHandler tests that context fields and redaction apply to custom handlers
+func TestContextHandlerCustomHandler(t *testing.T) {
+	handler := &recordingHandler{}
+	log := slog.New(newContextHandler(handler, levels, nil))
+
+	ctx := pkgContext.WithRequestID(context.Background(), "req-1")
+	log.InfoContext(ctx, "login", "password", "hunter2", "user", slog.GroupValue(slog.String("token", "abc")))
+
+	if len(handler.records) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(handler.records))
+	}
+
+	attrs := recordAttrs(handler.records[0])
+	if attrs["request_id"].String() != "req-1" {
+		t.Errorf("Expected request_id from context, got %v", attrs["request_id"])
+	}
+	if attrs["password"].String() != "[REDACTED]" {
+		t.Errorf("Expected password to be redacted, got %v", attrs["password"])
+	}
+	if token := attrs["user"].Group()[0]; token.Value.String() != "[REDACTED]" {
+		t.Errorf("Expected grouped token to be redacted, got %v", token)
+	}
+}
+
+// TestFormats tests the built-in output formats
+func TestFormats(t *testing.T) {
+	testCases := []struct {
+		format   Format
+		expected []string
+	}{
+		{FormatJSON, []string{`"msg":"Order created"`, `"order_id":42`, `"request_id":"req-1"`, `"payment":{"method":"card"}`}},
+		{FormatLogfmt, []string{`msg="Order created"`, `order_id=42`, `request_id=req-1`, `payment.method=card`}},
+		{FormatConsole, []string{"INF", "Order created", "order_id=" + colorReset + "42", "request_id=" + colorReset + "req-1", "payment.method=" + colorReset + "card"}},
+	}
+
+	ctx := pkgContext.WithRequestID(context.Background(), "req-1")
+
+	for _, testCase := range testCases {
+		var buffer bytes.Buffer
+		log := slog.New(newContextHandler(newFormatHandler(testCase.format, &buffer, nil), levels, nil))
+
+		log.With("order_id", 42).InfoContext(ctx, "Order created", slog.Group("payment", "method", "card"))
+		log.DebugContext(ctx, "Hidden")
+
+		output := buffer.String()
+		for _, expected := range testCase.expected {
+			if !strings.Contains(output, expected) {
+				t.Errorf("Format %d: expected output to contain %q, got %s", testCase.format, expected, output)
+			}
+		}
+		if strings.Contains(output, "Hidden") || strings.Count(output, "\n") != 1 {
+			t.Errorf("Format %d: expected a single Info line, got %s", testCase.format, output)
+		}
+	}
+}
+
+// TestConsoleHandlerErrorValue tests that syserr errors are flattened in console output
+func TestConsoleHandlerErrorValue(t *testing.T) {
+	var buffer bytes.Buffer
+	log := slog.New(newConsoleHandler(&buffer, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	log.Error("failed", "error", syserr.New(syserr.NotFoundCode, "no rows"))
+
+	output := buffer.String()
+	if !strings.Contains(output, "ERR") || !strings.Contains(output, "error.code="+colorReset+"not_found") {
+		t.Errorf("Unexpected console output %s", output)
+	}
+}
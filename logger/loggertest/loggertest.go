@@ -0,0 +1,318 @@
+// Package loggertest captures the records of a logger in memory so that tests can assert on them.
+package loggertest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/syserr"
+)
+
+// Record is a captured log record
+type Record struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	// Attrs holds the attributes of the record, including context fields.
+	// Keys of grouped attributes are joined with dots, e.g. "order.id".
+	Attrs map[string]any
+}
+
+// Attr returns the value of the attribute
+func (r Record) Attr(key string) (any, bool) {
+	value, ok := r.Attrs[key]
+	return value, ok
+}
+
+// Err returns the error of the "err" or "error" attribute, or nil
+func (r Record) Err() error {
+	for _, key := range []string{"err", "error"} {
+		if err, ok := r.Attrs[key].(error); ok {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r Record) String() string {
+	return fmt.Sprintf("%s %q %v", r.Level, r.Message, r.Attrs)
+}
+
+// Recorder captures the records of its logger. It is safe for concurrent use,
+// and each test gets its own recorder so that parallel tests do not see each other records.
+type Recorder struct {
+	t       testing.TB
+	log     *logger.Logger
+	mu      sync.Mutex
+	records []Record
+}
+
+// New creates a recorder capturing every level, including Debug
+func New(t testing.TB) *Recorder {
+	t.Helper()
+
+	r := &Recorder{t: t}
+	r.log = logger.New(&logger.Config{Level: slog.LevelDebug, Handler: &handler{recorder: r}})
+
+	return r
+}
+
+// Install creates a recorder and makes its logger the default logger until the end of the test.
+// Tests using it must not run in parallel; prefer Context when the code under test logs with a context.
+func Install(t testing.TB) *Recorder {
+	t.Helper()
+
+	r := New(t)
+	previous := logger.Default()
+	logger.SetDefault(r.log)
+	t.Cleanup(func() {
+		logger.SetDefault(previous)
+	})
+
+	return r
+}
+
+// Logger returns the logger writing to the recorder
+func (r *Recorder) Logger() *logger.Logger {
+	return r.log
+}
+
+// Context returns a context carrying the recorder logger, used by the logger package functions
+func (r *Recorder) Context(ctx context.Context) context.Context {
+	return logger.WithLogger(ctx, r.log)
+}
+
+// Records returns the captured records
+func (r *Recorder) Records() []Record {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Record(nil), r.records...)
+}
+
+// Reset removes the captured records
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.records = nil
+}
+
+// Find returns the records matching all the matchers
+func (r *Recorder) Find(matchers ...Matcher) []Record {
+	var result []Record
+
+	for _, record := range r.Records() {
+		if matchAll(record, matchers) {
+			result = append(result, record)
+		}
+	}
+
+	return result
+}
+
+// Expect fails the test unless a record matches all the matchers, and returns the first match
+func (r *Recorder) Expect(matchers ...Matcher) Record {
+	r.t.Helper()
+
+	found := r.Find(matchers...)
+	if len(found) == 0 {
+		r.t.Errorf("Expected a log record with %s, got:\n%s", describe(matchers), r.dump())
+		return Record{}
+	}
+
+	return found[0]
+}
+
+// ExpectNone fails the test if a record matches all the matchers
+func (r *Recorder) ExpectNone(matchers ...Matcher) {
+	r.t.Helper()
+
+	if found := r.Find(matchers...); len(found) > 0 {
+		r.t.Errorf("Expected no log record with %s, got:\n%s", describe(matchers), r.dump())
+	}
+}
+
+func (r *Recorder) add(record Record) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.records = append(r.records, record)
+}
+
+func (r *Recorder) dump() string {
+	records := r.Records()
+	if len(records) == 0 {
+		return "  no records"
+	}
+
+	lines := make([]string, len(records))
+	for index, record := range records {
+		lines[index] = "  " + record.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Matcher selects records
+type Matcher struct {
+	description string
+	match       func(Record) bool
+}
+
+func (m Matcher) String() string {
+	return m.description
+}
+
+// Level matches records of the level
+func Level(level slog.Level) Matcher {
+	return Matcher{
+		description: "level=" + level.String(),
+		match:       func(r Record) bool { return r.Level == level },
+	}
+}
+
+// Message matches records with the message
+func Message(message string) Matcher {
+	return Matcher{
+		description: fmt.Sprintf("message=%q", message),
+		match:       func(r Record) bool { return r.Message == message },
+	}
+}
+
+// MessageContains matches records whose message contains the substring
+func MessageContains(substring string) Matcher {
+	return Matcher{
+		description: fmt.Sprintf("message containing %q", substring),
+		match:       func(r Record) bool { return strings.Contains(r.Message, substring) },
+	}
+}
+
+// Attr matches records with the attribute. Values are equal when they are deeply equal
+// or have the same string form, so that Attr("code", "not_found") matches a syserr.Code.
+func Attr(key string, value any) Matcher {
+	return Matcher{
+		description: fmt.Sprintf("%s=%v", key, value),
+		match: func(r Record) bool {
+			actual, ok := r.Attrs[key]
+			return ok && (reflect.DeepEqual(actual, value) || fmt.Sprint(actual) == fmt.Sprint(value))
+		},
+	}
+}
+
+// Code matches records of errors with the code, logged with LogError or with an "err" attribute
+func Code(code syserr.Code) Matcher {
+	return Matcher{
+		description: "code=" + string(code),
+		match: func(r Record) bool {
+			if err := r.Err(); err != nil && syserr.GetCodeFromGenericError(err) == code {
+				return true
+			}
+			value, ok := r.Attrs["code"]
+			return ok && fmt.Sprint(value) == string(code)
+		},
+	}
+}
+
+// Err matches records whose "err" or "error" attribute matches the target with errors.Is
+func Err(target error) Matcher {
+	return Matcher{
+		description: fmt.Sprintf("err=%v", target),
+		match:       func(r Record) bool { return errors.Is(r.Err(), target) },
+	}
+}
+
+func matchAll(record Record, matchers []Matcher) bool {
+	for _, matcher := range matchers {
+		if !matcher.match(record) {
+			return false
+		}
+	}
+	return true
+}
+
+func describe(matchers []Matcher) string {
+	if len(matchers) == 0 {
+		return "any content"
+	}
+
+	descriptions := make([]string, len(matchers))
+	for index, matcher := range matchers {
+		descriptions[index] = matcher.description
+	}
+	return strings.Join(descriptions, ", ")
+}
+
+// handler converts slog records into Records, keeping the attributes and groups of WithAttrs and WithGroup
+type handler struct {
+	recorder *Recorder
+	attrs    map[string]any
+	prefix   string
+}
+
+func (h *handler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+func (h *handler) Handle(_ context.Context, record slog.Record) error {
+	attrs := make(map[string]any, len(h.attrs)+record.NumAttrs())
+	for key, value := range h.attrs {
+		attrs[key] = value
+	}
+
+	record.Attrs(func(attr slog.Attr) bool {
+		addAttr(attrs, h.prefix, attr)
+		return true
+	})
+
+	h.recorder.add(Record{Time: record.Time, Level: record.Level, Message: record.Message, Attrs: attrs})
+	return nil
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := &handler{recorder: h.recorder, attrs: make(map[string]any, len(h.attrs)+len(attrs)), prefix: h.prefix}
+	for key, value := range h.attrs {
+		clone.attrs[key] = value
+	}
+	for _, attr := range attrs {
+		addAttr(clone.attrs, h.prefix, attr)
+	}
+	return clone
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &handler{recorder: h.recorder, attrs: h.attrs, prefix: h.prefix + name + "."}
+}
+
+func addAttr(attrs map[string]any, prefix string, attr slog.Attr) {
+	// Keep errors such as *syserr.Error rather than their log value, for Err and Code
+	if err, ok := attr.Value.Any().(error); ok && attr.Value.Kind() == slog.KindLogValuer {
+		attrs[prefix+attr.Key] = err
+		return
+	}
+
+	value := attr.Value.Resolve()
+
+	if value.Kind() != slog.KindGroup {
+		attrs[prefix+attr.Key] = value.Any()
+		return
+	}
+
+	// Inline groups without a key, as slog handlers do
+	if attr.Key != "" {
+		prefix += attr.Key + "."
+	}
+	for _, member := range value.Group() {
+		addAttr(attrs, prefix, member)
+	}
+}
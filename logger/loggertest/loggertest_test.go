@@ -0,0 +1,84 @@
+package loggertest
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	pkgContext "github.com/duongptryu/gox/context"
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/syserr"
+)
+
+// TestRecorder tests that records are captured with their attributes, groups and context fields
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	recorder := New(t)
+	ctx := recorder.Context(pkgContext.WithRequestID(context.Background(), "req-1"))
+	ctx = logger.WithFields(ctx, logger.F("tenant_id", "acme"))
+
+	logger.Debug(ctx, "Loading order", logger.F("order_id", 42))
+	logger.LogError(ctx, syserr.New(syserr.NotFoundCode, "order not found"))
+
+	slog.New(recorder.Logger().Handler()).With("service", "orders", "err", syserr.New(syserr.InternalCode, "gateway timeout")).
+		WithGroup("order").ErrorContext(ctx, "Payment failed", "id", 42)
+
+	records := recorder.Records()
+	if len(records) != 3 {
+		t.Fatalf("Expected 3 records, got %d", len(records))
+	}
+
+	recorder.Expect(Level(slog.LevelDebug), Message("Loading order"), Attr("order_id", 42), Attr("request_id", "req-1"))
+	recorder.Expect(Level(slog.LevelWarn), Code(syserr.NotFoundCode), Attr("tenant_id", "acme"))
+
+	record := recorder.Expect(Level(slog.LevelError), Code(syserr.InternalCode), Attr("service", "orders"), Attr("order.id", 42))
+	if record.Err() == nil || record.Err().Error() != "gateway timeout" {
+		t.Errorf("Expected the error attribute, got %v", record.Err())
+	}
+
+	recorder.ExpectNone(Level(slog.LevelError), Code(syserr.NotFoundCode))
+
+	recorder.Reset()
+	if len(recorder.Records()) != 0 {
+		t.Error("Expected no records after reset")
+	}
+}
+
+// TestRecorderFailures tests that failed expectations are reported to the test
+func TestRecorderFailures(t *testing.T) {
+	t.Parallel()
+
+	inner := &testing.T{}
+	recorder := New(inner)
+	target := errors.New("connection refused")
+
+	recorder.Logger().Error(context.Background(), "Dial failed", logger.F("err", target))
+
+	recorder.Expect(Err(target), MessageContains("Dial"))
+	if inner.Failed() {
+		t.Fatal("Expected the matching record to be found")
+	}
+
+	recorder.Expect(Message("Dial succeeded"))
+	if !inner.Failed() {
+		t.Error("Expected a missing record to fail the test")
+	}
+}
+
+// TestInstall tests that the default logger is replaced for the test and restored afterwards
+func TestInstall(t *testing.T) {
+	previous := logger.Default()
+
+	t.Run("installed", func(t *testing.T) {
+		recorder := Install(t)
+		logger.Named("messaging").Info(context.Background(), "Command handled")
+
+		recorder.Expect(Message("Command handled"), Attr("logger", "messaging"))
+	})
+
+	if logger.Default() != previous {
+		t.Error("Expected the default logger to be restored")
+	}
+}
@@ -0,0 +1,493 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultBatchSize       = 100
+	defaultFlushInterval   = time.Second
+	defaultNetworkBuffer   = 10000
+	defaultMaxRetries      = 3
+	defaultRetryBackoff    = 100 * time.Millisecond
+	defaultMaxRetryBackoff = 5 * time.Second
+	defaultMaxSpillSize    = 100 << 20
+)
+
+// NetworkConfig configures how records are batched and retried by the network sinks
+type NetworkConfig struct {
+	// BatchSize is the maximum number of records sent at once, 100 by default
+	BatchSize int
+	// FlushInterval is the maximum time a record waits for its batch to fill up, 1s by default
+	FlushInterval time.Duration
+	// BufferSize is the number of records queued before new ones are dropped, 10000 by default
+	BufferSize int
+	// MaxRetries is the number of retries of a failed batch, 3 by default, negative disables retries
+	MaxRetries int
+	// RetryBackoff is the wait before the first retry, doubled for each retry up to MaxRetryBackoff
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration
+	// SpillPath is the file batches are appended to when they cannot be sent, they are sent again
+	// once the collector is back. Empty drops the batches.
+	SpillPath string
+	// MaxSpillSize is the maximum size of the spill file in bytes, 100MB by default
+	MaxSpillSize int64
+}
+
+func (c NetworkConfig) withDefaults() NetworkConfig {
+	if c.BatchSize <= 0 {
+		c.BatchSize = defaultBatchSize
+	}
+	if c.FlushInterval <= 0 {
+		c.FlushInterval = defaultFlushInterval
+	}
+	if c.BufferSize <= 0 {
+		c.BufferSize = defaultNetworkBuffer
+	}
+	if c.MaxRetries == 0 {
+		c.MaxRetries = defaultMaxRetries
+	}
+	if c.RetryBackoff <= 0 {
+		c.RetryBackoff = defaultRetryBackoff
+	}
+	if c.MaxRetryBackoff <= 0 {
+		c.MaxRetryBackoff = defaultMaxRetryBackoff
+	}
+	if c.MaxSpillSize <= 0 {
+		c.MaxSpillSize = defaultMaxSpillSize
+	}
+	return c
+}
+
+// transport sends a batch of encoded records
+type transport interface {
+	send(ctx context.Context, batch [][]byte) error
+	close() error
+}
+
+// permanentError is returned by transports for batches that must not be retried or spilled
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// NetworkHandler is a slog handler sending records to a collector in batches from a background goroutine.
+// It is created by NewSyslogHandler and NewHTTPHandler.
+type NetworkHandler struct {
+	handler slog.Handler
+	encoder *networkEncoder
+}
+
+// networkEncoder turns the output of the wrapped handler into entries for the shipper.
+// Records are encoded one at a time so that the entry can be framed with the level and time of its record.
+type networkEncoder struct {
+	mu      sync.Mutex
+	record  slog.Record
+	frame   func(record slog.Record, message []byte) []byte
+	shipper *shipper
+}
+
+func newNetworkHandler(config NetworkConfig, transport transport, opts *slog.HandlerOptions,
+	frame func(record slog.Record, message []byte) []byte) *NetworkHandler {
+	encoder := &networkEncoder{frame: frame, shipper: newShipper(config.withDefaults(), transport)}
+
+	return &NetworkHandler{handler: slog.NewJSONHandler(encoder, opts), encoder: encoder}
+}
+
+func (h *NetworkHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *NetworkHandler) Handle(ctx context.Context, record slog.Record) error {
+	h.encoder.mu.Lock()
+	defer h.encoder.mu.Unlock()
+
+	h.encoder.record = record
+	return h.handler.Handle(ctx, record)
+}
+
+func (h *NetworkHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &NetworkHandler{handler: h.handler.WithAttrs(attrs), encoder: h.encoder}
+}
+
+func (h *NetworkHandler) WithGroup(name string) slog.Handler {
+	return &NetworkHandler{handler: h.handler.WithGroup(name), encoder: h.encoder}
+}
+
+// Flush sends the queued records and returns when they are sent, spilled or the context is done
+func (h *NetworkHandler) Flush(ctx context.Context) error {
+	return h.encoder.shipper.flush(ctx)
+}
+
+// Close sends the queued records and closes the connection to the collector
+func (h *NetworkHandler) Close() error {
+	return h.encoder.shipper.close()
+}
+
+// Dropped returns the number of records dropped because the queue was full or they could not be sent
+func (h *NetworkHandler) Dropped() uint64 {
+	return h.encoder.shipper.dropped.Load()
+}
+
+// Write receives the JSON line of the record being handled
+func (e *networkEncoder) Write(p []byte) (int, error) {
+	e.shipper.enqueue(e.frame(e.record, bytes.TrimSuffix(p, []byte("\n"))))
+	return len(p), nil
+}
+
+// shipper batches entries and sends them with retries, spilling the batches that cannot be sent
+type shipper struct {
+	config    NetworkConfig
+	transport transport
+	dropped   atomic.Uint64
+
+	queue   chan []byte
+	flushCh chan chan struct{}
+	done    chan struct{}
+
+	closeOnce sync.Once
+	closeErr  error
+	// closing is set when the queue is closed, so that enqueue does not send on it
+	mu      sync.RWMutex
+	closing bool
+
+	// spillPending, replayBackoff and nextReplay are owned by run. While the collector is down,
+	// the spill file is only replayed once the backoff of the last failure has elapsed.
+	spillPending  bool
+	replayBackoff time.Duration
+	nextReplay    time.Time
+}
+
+func newShipper(config NetworkConfig, transport transport) *shipper {
+	s := &shipper{
+		config:    config,
+		transport: transport,
+		queue:     make(chan []byte, config.BufferSize),
+		flushCh:   make(chan chan struct{}),
+		done:      make(chan struct{}),
+	}
+
+	// Replay the records spilled by a previous run
+	if config.SpillPath != "" {
+		_, err := os.Stat(config.SpillPath)
+		s.spillPending = err == nil
+	}
+
+	go s.run()
+
+	return s
+}
+
+func (s *shipper) enqueue(entry []byte) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closing {
+		s.dropped.Add(1)
+		return
+	}
+
+	select {
+	case s.queue <- entry:
+	default:
+		s.dropped.Add(1)
+	}
+}
+
+func (s *shipper) flush(ctx context.Context) error {
+	done := make(chan struct{})
+
+	select {
+	case s.flushCh <- done:
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *shipper) close() error {
+	s.closeOnce.Do(func() {
+		s.mu.Lock()
+		s.closing = true
+		close(s.queue)
+		s.mu.Unlock()
+
+		<-s.done
+		s.closeErr = s.transport.close()
+	})
+
+	return s.closeErr
+}
+
+func (s *shipper) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.config.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([][]byte, 0, s.config.BatchSize)
+
+	for {
+		select {
+		case entry, ok := <-s.queue:
+			if !ok {
+				s.ship(batch, true)
+				return
+			}
+
+			batch = append(batch, entry)
+			if len(batch) >= s.config.BatchSize {
+				s.ship(batch, false)
+				batch = make([][]byte, 0, s.config.BatchSize)
+			}
+		case <-ticker.C:
+			s.ship(batch, false)
+			batch = make([][]byte, 0, s.config.BatchSize)
+		case done := <-s.flushCh:
+			// Take the entries queued before the flush request
+			for pending := len(s.queue); pending > 0; pending-- {
+				entry, ok := <-s.queue
+				if !ok {
+					break
+				}
+				batch = append(batch, entry)
+			}
+			s.ship(batch, true)
+			batch = make([][]byte, 0, s.config.BatchSize)
+			close(done)
+		}
+	}
+}
+
+// ship sends the spilled batches, then the batch. Batches that cannot be sent are spilled.
+// Unless forced by a flush or close, the spill file is not replayed before the replay backoff has elapsed.
+func (s *shipper) ship(batch [][]byte, force bool) {
+	if s.spillPending {
+		if !force && time.Now().Before(s.nextReplay) {
+			// The collector was down recently, keep the order of the records
+			s.spill(batch)
+			return
+		}
+
+		if !s.replaySpill() {
+			s.replayFailed()
+			s.spill(batch)
+			return
+		}
+
+		s.spillPending = false
+		s.replayBackoff = 0
+	}
+
+	if len(batch) == 0 {
+		return
+	}
+
+	for start := 0; start < len(batch); start += s.config.BatchSize {
+		end := min(start+s.config.BatchSize, len(batch))
+		if err := s.sendWithRetry(batch[start:end]); err != nil {
+			var permanent *permanentError
+			if errors.As(err, &permanent) {
+				s.dropped.Add(uint64(end - start))
+				continue
+			}
+
+			if s.config.SpillPath != "" {
+				s.replayFailed()
+			}
+			s.spill(batch[start:])
+			return
+		}
+	}
+}
+
+// replayFailed schedules the next replay of the spill file, doubling the backoff up to MaxRetryBackoff
+func (s *shipper) replayFailed() {
+	if s.replayBackoff == 0 {
+		s.replayBackoff = s.config.RetryBackoff
+	} else {
+		s.replayBackoff = min(s.replayBackoff*2, s.config.MaxRetryBackoff)
+	}
+	s.nextReplay = time.Now().Add(s.replayBackoff)
+}
+
+func (s *shipper) sendWithRetry(batch [][]byte) error {
+	backoff := s.config.RetryBackoff
+
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = s.transport.send(context.Background(), batch)
+
+		var permanent *permanentError
+		if err == nil || errors.As(err, &permanent) || attempt >= s.config.MaxRetries {
+			return err
+		}
+
+		time.Sleep(backoff)
+		backoff = min(backoff*2, s.config.MaxRetryBackoff)
+	}
+}
+
+// spill appends the batch to the spill file, or drops it when there is no spill file or it is full
+func (s *shipper) spill(batch [][]byte) {
+	if len(batch) == 0 {
+		return
+	}
+
+	if s.config.SpillPath == "" {
+		s.dropped.Add(uint64(len(batch)))
+		return
+	}
+
+	file, err := os.OpenFile(s.config.SpillPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, defaultFileMode)
+	if err != nil {
+		s.dropped.Add(uint64(len(batch)))
+		return
+	}
+	defer file.Close()
+
+	size := int64(0)
+	if info, err := file.Stat(); err == nil {
+		size = info.Size()
+	}
+
+	s.spillPending = true
+
+	writer := bufio.NewWriter(file)
+	for index, entry := range batch {
+		if size+int64(len(entry))+1 > s.config.MaxSpillSize {
+			s.dropped.Add(uint64(len(batch) - index))
+			break
+		}
+
+		_, _ = writer.Write(entry)
+		_ = writer.WriteByte('\n')
+		size += int64(len(entry)) + 1
+	}
+
+	if err := writer.Flush(); err != nil {
+		s.dropped.Add(uint64(len(batch)))
+	}
+}
+
+// replaySpill sends the spilled entries in batches and removes the spill file, it reports whether the file is empty.
+// The file is read one batch at a time, when a batch fails the entries not sent yet are kept in the file.
+func (s *shipper) replaySpill() bool {
+	file, err := os.Open(s.config.SpillPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return true
+	}
+	if err != nil {
+		return false
+	}
+
+	reader := bufio.NewReader(file)
+	// sent is the offset of the first entry not sent yet
+	sent := int64(0)
+
+	for {
+		entries, size, err := readEntries(reader, s.config.BatchSize)
+		if err != nil {
+			_ = file.Close()
+			return false
+		}
+		if len(entries) > 0 {
+			// A single attempt, the batch being shipped is retried
+			err = s.transport.send(context.Background(), entries)
+
+			var permanent *permanentError
+			if errors.As(err, &permanent) {
+				s.dropped.Add(uint64(len(entries)))
+				err = nil
+			}
+			if err != nil {
+				if sent > 0 {
+					s.rewriteSpill(file, sent)
+				}
+				_ = file.Close()
+				return false
+			}
+		}
+
+		sent += size
+		if len(entries) < s.config.BatchSize {
+			break
+		}
+	}
+
+	_ = file.Close()
+	_ = os.Remove(s.config.SpillPath)
+	return true
+}
+
+// rewriteSpill replaces the spill file with its entries from offset on, those not sent yet
+func (s *shipper) rewriteSpill(file *os.File, offset int64) {
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return
+	}
+
+	temporary := s.config.SpillPath + ".tmp"
+	output, err := os.OpenFile(temporary, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, defaultFileMode)
+	if err != nil {
+		return
+	}
+
+	_, err = io.Copy(output, file)
+	if closeErr := output.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(temporary)
+		return
+	}
+
+	_ = os.Rename(temporary, s.config.SpillPath)
+}
+
+// readEntries reads up to count non-empty lines, it returns the entries and the number of bytes read
+func readEntries(reader *bufio.Reader, count int) ([][]byte, int64, error) {
+	var entries [][]byte
+	size := int64(0)
+
+	for len(entries) < count {
+		line, err := reader.ReadBytes('\n')
+		size += int64(len(line))
+
+		if entry := bytes.TrimSuffix(line, []byte("\n")); len(entry) > 0 {
+			entries = append(entries, entry)
+		}
+
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return entries, size, nil
+}
@@ -0,0 +1,385 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// readOctetCounted reads RFC 6587 octet-counted syslog messages until the connection is closed
+func readOctetCounted(t *testing.T, conn net.Conn) []string {
+	t.Helper()
+
+	var messages []string
+	reader := bufio.NewReader(conn)
+	for {
+		length, err := reader.ReadString(' ')
+		if err != nil {
+			return messages
+		}
+
+		size, err := strconv.Atoi(strings.TrimSpace(length))
+		if err != nil {
+			t.Errorf("Invalid frame length %q", length)
+			return messages
+		}
+
+		message := make([]byte, size)
+		if _, err := io.ReadFull(reader, message); err != nil {
+			t.Errorf("Truncated frame: %v", err)
+			return messages
+		}
+		messages = append(messages, string(message))
+	}
+}
+
+// acceptMessages accepts a single connection and returns its messages once it is closed
+func acceptMessages(t *testing.T, listener net.Listener) <-chan []string {
+	result := make(chan []string, 1)
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			result <- nil
+			return
+		}
+		defer conn.Close()
+		result <- readOctetCounted(t, conn)
+	}()
+
+	return result
+}
+
+func checkSyslogMessage(t *testing.T, message string, priority string, text string) {
+	t.Helper()
+
+	if !strings.HasPrefix(message, priority+"1 ") || !strings.Contains(message, " orders-api ") {
+		t.Errorf("Unexpected syslog header in %q", message)
+	}
+
+	body := message[strings.Index(message, "{"):]
+	var record map[string]any
+	if err := json.Unmarshal([]byte(body), &record); err != nil {
+		t.Fatalf("Expected a JSON message, got %q", body)
+	}
+	if record["msg"] != text {
+		t.Errorf("Expected message %q, got %v", text, record["msg"])
+	}
+}
+
+// TestSyslogUDP tests that records are sent as one RFC 5424 datagram each
+func TestSyslogUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	handler, err := NewSyslogHandler(SyslogConfig{Network: "udp", Address: conn.LocalAddr().String(), AppName: "orders-api"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	log := New(&Config{Handler: handler})
+	log.Warning(context.Background(), "Stock low")
+	log.Error(context.Background(), "Payment failed")
+	if err := log.Close(context.Background()); err != nil {
+		t.Fatalf("Unexpected close error: %v", err)
+	}
+
+	buffer := make([]byte, 4096)
+	for _, expected := range []struct{ priority, text string }{{"<12>", "Stock low"}, {"<11>", "Payment failed"}} {
+		_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, err := conn.ReadFrom(buffer)
+		if err != nil {
+			t.Fatalf("Failed to read datagram: %v", err)
+		}
+		checkSyslogMessage(t, string(buffer[:n]), expected.priority, expected.text)
+	}
+}
+
+// TestSyslogTCPAndTLS tests octet-counted framing over TCP and TLS
+func TestSyslogTCPAndTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	tests := []struct {
+		network string
+		listen  func() (net.Listener, error)
+		client  *tls.Config
+	}{
+		{
+			network: "tcp",
+			listen:  func() (net.Listener, error) { return net.Listen("tcp", "127.0.0.1:0") },
+		},
+		{
+			network: "tls",
+			listen: func() (net.Listener, error) {
+				return tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: server.TLS.Certificates})
+			},
+			client: server.Client().Transport.(*http.Transport).TLSClientConfig,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.network, func(t *testing.T) {
+			listener, err := test.listen()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer listener.Close()
+			received := acceptMessages(t, listener)
+
+			handler, err := NewSyslogHandler(SyslogConfig{
+				Network:   test.network,
+				Address:   listener.Addr().String(),
+				TLSConfig: test.client,
+				AppName:   "orders-api",
+				Facility:  FacilityLocal0,
+			}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			log := New(&Config{Handler: handler})
+			log.Info(context.Background(), "Order created")
+			log.Info(context.Background(), "Order shipped")
+			if err := log.Close(context.Background()); err != nil {
+				t.Fatalf("Unexpected close error: %v", err)
+			}
+
+			messages := <-received
+			if len(messages) != 2 {
+				t.Fatalf("Expected 2 messages, got %v", messages)
+			}
+			checkSyslogMessage(t, messages[0], "<134>", "Order created")
+			checkSyslogMessage(t, messages[1], "<134>", "Order shipped")
+		})
+	}
+}
+
+// collector is an HTTP log collector that can be made unavailable
+type collector struct {
+	mu        sync.Mutex
+	messages  []string
+	requests  atomic.Int32
+	available atomic.Bool
+	status    int
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.requests.Add(1)
+
+	if !c.available.Load() {
+		w.WriteHeader(c.status)
+		return
+	}
+
+	var records []map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&records); err != nil || r.Header.Get("X-API-Key") != "secret" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, record := range records {
+		c.messages = append(c.messages, record["msg"].(string))
+	}
+}
+
+func (c *collector) received() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.messages...)
+}
+
+// TestHTTPSinkRetries tests batching and retries of server errors
+func TestHTTPSinkRetries(t *testing.T) {
+	collector := &collector{status: http.StatusServiceUnavailable}
+	server := httptest.NewServer(collector)
+	defer server.Close()
+
+	handler := NewHTTPHandler(HTTPConfig{
+		URL:      server.URL,
+		Headers:  map[string]string{"X-API-Key": "secret"},
+		Batching: NetworkConfig{BatchSize: 10, FlushInterval: time.Hour, MaxRetries: 5, RetryBackoff: time.Millisecond},
+	}, nil)
+	log := New(&Config{Handler: handler})
+
+	for _, message := range []string{"first", "second", "third"} {
+		log.Info(context.Background(), message)
+	}
+
+	// The collector recovers after a few failed attempts
+	go func() {
+		for collector.requests.Load() < 2 {
+			time.Sleep(time.Millisecond)
+		}
+		collector.available.Store(true)
+	}()
+
+	if err := log.Flush(context.Background()); err != nil {
+		t.Fatalf("Unexpected flush error: %v", err)
+	}
+
+	if got := collector.received(); strings.Join(got, ",") != "first,second,third" {
+		t.Errorf("Expected the batch after retries, got %v", got)
+	}
+	if handler.Dropped() != 0 {
+		t.Errorf("Expected no dropped records, got %d", handler.Dropped())
+	}
+	_ = log.Close(context.Background())
+}
+
+// TestHTTPSinkSpill tests that batches are spilled to disk while the collector is down and sent in order afterwards
+func TestHTTPSinkSpill(t *testing.T) {
+	collector := &collector{status: http.StatusBadGateway}
+	server := httptest.NewServer(collector)
+	defer server.Close()
+
+	spillPath := filepath.Join(t.TempDir(), "spill.log")
+	handler := NewHTTPHandler(HTTPConfig{
+		URL:      server.URL,
+		Headers:  map[string]string{"X-API-Key": "secret"},
+		Batching: NetworkConfig{BatchSize: 2, FlushInterval: time.Hour, MaxRetries: -1, SpillPath: spillPath},
+	}, nil)
+	log := New(&Config{Handler: handler})
+	ctx := context.Background()
+
+	log.Info(ctx, "first")
+	log.Info(ctx, "second")
+	log.Info(ctx, "third")
+	_ = log.Flush(ctx)
+
+	spilled, err := os.ReadFile(spillPath)
+	if err != nil || strings.Count(string(spilled), "\n") != 3 {
+		t.Fatalf("Expected 3 spilled records, got %q (%v)", spilled, err)
+	}
+
+	collector.available.Store(true)
+	log.Info(ctx, "fourth")
+	_ = log.Flush(ctx)
+
+	if got := collector.received(); strings.Join(got, ",") != "first,second,third,fourth" {
+		t.Errorf("Expected the spilled records before the new one, got %v", got)
+	}
+	if _, err := os.Stat(spillPath); !os.IsNotExist(err) {
+		t.Errorf("Expected the spill file to be removed, got %v", err)
+	}
+	_ = log.Close(ctx)
+}
+
+// TestHTTPSinkSpillBackoff tests that the spill file is not replayed on every tick while the collector is down
+func TestHTTPSinkSpillBackoff(t *testing.T) {
+	collector := &collector{status: http.StatusBadGateway}
+	server := httptest.NewServer(collector)
+	defer server.Close()
+
+	handler := NewHTTPHandler(HTTPConfig{
+		URL: server.URL,
+		Batching: NetworkConfig{
+			FlushInterval:   time.Millisecond,
+			MaxRetries:      -1,
+			RetryBackoff:    20 * time.Millisecond,
+			MaxRetryBackoff: 40 * time.Millisecond,
+			SpillPath:       filepath.Join(t.TempDir(), "spill.log"),
+		},
+	}, nil)
+	log := New(&Config{Handler: handler})
+
+	log.Info(context.Background(), "spilled")
+	time.Sleep(200 * time.Millisecond)
+
+	// About one replay every 40ms rather than one every tick
+	if requests := collector.requests.Load(); requests < 2 || requests > 10 {
+		t.Errorf("Expected the replays to back off, got %d requests", requests)
+	}
+	_ = log.Close(context.Background())
+}
+
+// failingTransport accepts a number of batches, then fails
+type failingTransport struct {
+	accepted int
+	batches  [][][]byte
+}
+
+func (t *failingTransport) send(_ context.Context, batch [][]byte) error {
+	if len(t.batches) == t.accepted {
+		return io.ErrUnexpectedEOF
+	}
+	t.batches = append(t.batches, batch)
+	return nil
+}
+
+func (t *failingTransport) close() error {
+	return nil
+}
+
+// TestReplaySpillPartial tests that the entries not sent when the collector fails again are kept in the spill file
+func TestReplaySpillPartial(t *testing.T) {
+	spillPath := filepath.Join(t.TempDir(), "spill.log")
+	if err := os.WriteFile(spillPath, []byte("1\n2\n3\n4\n5\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	transport := &failingTransport{accepted: 1}
+	s := &shipper{config: NetworkConfig{BatchSize: 2, SpillPath: spillPath}.withDefaults(), transport: transport}
+
+	if s.replaySpill() {
+		t.Fatal("Expected the replay to fail")
+	}
+	if len(transport.batches) != 1 || string(bytes.Join(transport.batches[0], []byte(","))) != "1,2" {
+		t.Errorf("Expected the first batch to be sent, got %q", transport.batches)
+	}
+
+	remaining, _ := os.ReadFile(spillPath)
+	if string(remaining) != "3\n4\n5\n" {
+		t.Errorf("Expected the entries not sent to be kept, got %q", remaining)
+	}
+
+	transport.accepted = 3
+	if !s.replaySpill() {
+		t.Fatal("Expected the replay to succeed")
+	}
+	if len(transport.batches) != 3 || string(transport.batches[2][0]) != "5" {
+		t.Errorf("Expected the remaining entries to be sent, got %q", transport.batches)
+	}
+	if _, err := os.Stat(spillPath); !os.IsNotExist(err) {
+		t.Errorf("Expected the spill file to be removed, got %v", err)
+	}
+}
+
+// TestHTTPSinkPermanentError tests that rejected batches are dropped without retries
+func TestHTTPSinkPermanentError(t *testing.T) {
+	collector := &collector{}
+	collector.available.Store(true)
+	server := httptest.NewServer(collector)
+	defer server.Close()
+
+	// Without the API key the collector rejects the batch
+	handler := NewHTTPHandler(HTTPConfig{URL: server.URL, Batching: NetworkConfig{RetryBackoff: time.Millisecond}}, nil)
+	log := New(&Config{Handler: handler})
+
+	log.Info(context.Background(), "rejected")
+	_ = log.Flush(context.Background())
+
+	if handler.Dropped() != 1 || collector.requests.Load() != 1 {
+		t.Errorf("Expected 1 dropped record and 1 request, got %d and %d", handler.Dropped(), collector.requests.Load())
+	}
+	_ = log.Close(context.Background())
+}
@@ -0,0 +1,249 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	defaultSamplingInterval    = time.Second
+	defaultSamplingReport      = time.Minute
+	defaultSamplingMaxMessages = 1000
+)
+
+// overflowSampledMessage is the sampled_message reported for the records counted together once MaxMessages is reached
+const overflowSampledMessage = "(other messages)"
+
+// SamplingPolicy logs the first records of a message in each interval, then every Thereafter-th one
+type SamplingPolicy struct {
+	First int
+	// Thereafter logs every Nth record once First is reached, zero drops them all
+	Thereafter int
+}
+
+// SamplingConfig limits the volume of repeated records per level and message
+type SamplingConfig struct {
+	// Interval is the window in which records are counted. Zero uses one second.
+	Interval time.Duration
+	// Levels holds the policy of each sampled level, records of other levels are not sampled
+	Levels map[slog.Level]SamplingPolicy
+	// ReportInterval is how often the number of suppressed records is logged. Zero uses one minute.
+	// Reports are logged by a goroutine stopped by Logger.Close.
+	ReportInterval time.Duration
+	// MaxMessages is the number of messages counted separately per report interval. Records of further messages,
+	// e.g. messages with dynamic text, are counted together. Zero uses 1000 messages.
+	MaxMessages int
+}
+
+// samplingKey identifies the records counted together
+type samplingKey struct {
+	level   slog.Level
+	message string
+	// overflow marks the bucket of the messages exceeding MaxMessages
+	overflow bool
+}
+
+type samplingCounter struct {
+	windowStart time.Time
+	count       int
+	// suppressed is the number of records dropped since the last report
+	suppressed int
+}
+
+// sampler is the state shared by a sampling handler and its derived handlers
+type sampler struct {
+	config SamplingConfig
+	// root receives the suppressed counter records
+	root slog.Handler
+	now  func() time.Time
+
+	mu       sync.Mutex
+	counters map[samplingKey]*samplingCounter
+	// messages is the number of counters, the overflow buckets excluded
+	messages int
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	stopped  chan struct{}
+}
+
+// samplingHandler drops records exceeding the sampling policy of their level
+type samplingHandler struct {
+	handler slog.Handler
+	sampler *sampler
+}
+
+func newSamplingHandler(handler slog.Handler, config SamplingConfig) *samplingHandler {
+	if config.Interval <= 0 {
+		config.Interval = defaultSamplingInterval
+	}
+	if config.ReportInterval <= 0 {
+		config.ReportInterval = defaultSamplingReport
+	}
+	if config.MaxMessages <= 0 {
+		config.MaxMessages = defaultSamplingMaxMessages
+	}
+
+	s := &sampler{
+		config:   config,
+		root:     handler,
+		now:      time.Now,
+		counters: map[samplingKey]*samplingCounter{},
+		stop:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+	go s.run()
+
+	return &samplingHandler{handler: handler, sampler: s}
+}
+
+func (h *samplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *samplingHandler) Handle(ctx context.Context, record slog.Record) error {
+	if !h.sampler.allow(record.Level, record.Message) {
+		return nil
+	}
+
+	return h.handler.Handle(ctx, record)
+}
+
+func (h *samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &samplingHandler{handler: h.handler.WithAttrs(attrs), sampler: h.sampler}
+}
+
+func (h *samplingHandler) WithGroup(name string) slog.Handler {
+	return &samplingHandler{handler: h.handler.WithGroup(name), sampler: h.sampler}
+}
+
+// Flush logs the pending suppressed counters
+func (h *samplingHandler) Flush(ctx context.Context) error {
+	h.sampler.flushReport(ctx)
+	return nil
+}
+
+// Close stops the periodic report, records suppressed afterwards are only reported by Flush
+func (h *samplingHandler) Close() error {
+	h.sampler.stopOnce.Do(func() {
+		close(h.sampler.stop)
+	})
+	<-h.sampler.stopped
+	return nil
+}
+
+// run logs the suppressed counters every report interval until the handler is closed
+func (s *sampler) run() {
+	defer close(s.stopped)
+
+	ticker := time.NewTicker(s.config.ReportInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.flushReport(context.Background())
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// allow counts the record and reports whether it is logged
+func (s *sampler) allow(level slog.Level, message string) bool {
+	policy, ok := s.config.Levels[level]
+	if !ok {
+		return true
+	}
+
+	now := s.now()
+	key := samplingKey{level: level, message: message}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counter, ok := s.counters[key]
+	if !ok && s.messages >= s.config.MaxMessages {
+		key = samplingKey{level: level, overflow: true}
+		counter, ok = s.counters[key]
+	}
+	if !ok {
+		counter = &samplingCounter{windowStart: now}
+		s.counters[key] = counter
+		if !key.overflow {
+			s.messages++
+		}
+	}
+
+	if now.Sub(counter.windowStart) >= s.config.Interval {
+		counter.windowStart = now
+		counter.count = 0
+	}
+
+	counter.count++
+
+	if counter.count <= policy.First {
+		return true
+	}
+	if policy.Thereafter > 0 && (counter.count-policy.First)%policy.Thereafter == 0 {
+		return true
+	}
+
+	counter.suppressed++
+	return false
+}
+
+// flushReport logs one record per message with suppressed records and resets the counters
+func (s *sampler) flushReport(ctx context.Context) {
+	type suppressedRecords struct {
+		key   samplingKey
+		count int
+	}
+
+	s.mu.Lock()
+	now := s.now()
+
+	var pending []suppressedRecords
+	for key, counter := range s.counters {
+		if counter.suppressed > 0 {
+			pending = append(pending, suppressedRecords{key: key, count: counter.suppressed})
+			counter.suppressed = 0
+		}
+
+		// Forget messages not seen for a whole report interval
+		if now.Sub(counter.windowStart) >= s.config.ReportInterval {
+			delete(s.counters, key)
+			if !key.overflow {
+				s.messages--
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	sort.Slice(pending, func(i, j int) bool {
+		if pending[i].key.overflow != pending[j].key.overflow {
+			return !pending[i].key.overflow
+		}
+		return pending[i].key.message < pending[j].key.message
+	})
+
+	for _, records := range pending {
+		message := records.key.message
+		if records.key.overflow {
+			message = overflowSampledMessage
+		}
+
+		record := slog.NewRecord(now, records.key.level, "Log records suppressed by sampling", 0)
+		record.AddAttrs(
+			slog.String("sampled_message", message),
+			slog.Int("suppressed", records.count),
+		)
+
+		if s.root.Enabled(ctx, records.key.level) {
+			_ = s.root.Handle(ctx, record)
+		}
+	}
+}
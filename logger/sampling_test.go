@@ -0,0 +1,183 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// TestSamplingHandler tests first-N-then-every-M sampling and the suppressed counter
+func TestSamplingHandler(t *testing.T) {
+	recorder := &recordingHandler{}
+	handler := newSamplingHandler(recorder, SamplingConfig{
+		Interval:       time.Second,
+		Levels:         map[slog.Level]SamplingPolicy{slog.LevelInfo: {First: 2, Thereafter: 3}},
+		ReportInterval: time.Minute,
+	})
+	defer handler.Close()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	handler.sampler.now = func() time.Time { return now }
+
+	log := slog.New(handler.WithAttrs([]slog.Attr{slog.String("component", "cqrs")}))
+	ctx := context.Background()
+
+	for i := 0; i < 10; i++ {
+		log.InfoContext(ctx, "Command handled")
+		log.WarnContext(ctx, "Not sampled")
+	}
+	log.InfoContext(ctx, "Event handled")
+
+	counts := map[string]int{}
+	for _, record := range recorder.records {
+		counts[record.Message]++
+	}
+
+	// Records 1, 2, 5 and 8 are logged
+	if counts["Command handled"] != 4 || counts["Not sampled"] != 10 || counts["Event handled"] != 1 {
+		t.Errorf("Unexpected counts %v", counts)
+	}
+
+	// A new interval starts over
+	now = now.Add(time.Second)
+	recorder.records = nil
+	log.InfoContext(ctx, "Command handled")
+	if len(recorder.records) != 1 {
+		t.Fatalf("Expected the first record of a new interval to be logged, got %d", len(recorder.records))
+	}
+
+	// The report logs the suppressed counter
+	now = now.Add(time.Minute)
+	recorder.records = nil
+	handler.sampler.flushReport(ctx)
+
+	if len(recorder.records) != 1 {
+		t.Fatalf("Expected the report, got %d records", len(recorder.records))
+	}
+
+	attrs := recordAttrs(recorder.records[0])
+	if recorder.records[0].Message != "Log records suppressed by sampling" ||
+		attrs["sampled_message"].String() != "Command handled" || attrs["suppressed"].Int64() != 6 {
+		t.Errorf("Unexpected report %v %v", recorder.records[0].Message, attrs)
+	}
+}
+
+// TestSamplingFlush tests that Flush logs pending counters
+func TestSamplingFlush(t *testing.T) {
+	recorder := &recordingHandler{}
+	handler := newSamplingHandler(recorder, SamplingConfig{
+		Levels: map[slog.Level]SamplingPolicy{slog.LevelError: {First: 1}},
+	})
+	defer handler.Close()
+
+	log := slog.New(handler)
+	for i := 0; i < 3; i++ {
+		log.Error("Payment failed")
+	}
+
+	if err := handler.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	last := recorder.records[len(recorder.records)-1]
+	if len(recorder.records) != 2 || recordAttrs(last)["suppressed"].Int64() != 2 {
+		t.Errorf("Expected one record and a report of 2 suppressed records, got %d records", len(recorder.records))
+	}
+}
+
+// channelHandler sends the records it handles to a channel, it is safe for concurrent use
+type channelHandler struct {
+	records chan slog.Record
+}
+
+func (h *channelHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *channelHandler) Handle(_ context.Context, record slog.Record) error {
+	h.records <- record
+	return nil
+}
+
+func (h *channelHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h *channelHandler) WithGroup(string) slog.Handler { return h }
+
+// TestSamplingReportTicker tests that suppressed counters are reported without further records, until Close
+func TestSamplingReportTicker(t *testing.T) {
+	recorder := &channelHandler{records: make(chan slog.Record, 10)}
+	handler := newSamplingHandler(recorder, SamplingConfig{
+		Levels:         map[slog.Level]SamplingPolicy{slog.LevelError: {First: 1}},
+		ReportInterval: 10 * time.Millisecond,
+	})
+
+	log := slog.New(handler)
+	for i := 0; i < 3; i++ {
+		log.Error("Payment failed")
+	}
+	<-recorder.records
+
+	select {
+	case record := <-recorder.records:
+		if attrs := recordAttrs(record); attrs["suppressed"].Int64() != 2 {
+			t.Errorf("Expected a report of 2 suppressed records, got %v", attrs)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the report to be logged by the ticker")
+	}
+
+	if err := handler.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := handler.Close(); err != nil {
+		t.Fatalf("Expected Close to be idempotent, got %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		log.Error("Payment failed")
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(recorder.records)
+	for record := range recorder.records {
+		if record.Message == "Log records suppressed by sampling" {
+			t.Error("Expected no report once closed")
+		}
+	}
+}
+
+// TestSamplingMaxMessages tests that messages exceeding MaxMessages are counted in one bucket
+func TestSamplingMaxMessages(t *testing.T) {
+	recorder := &recordingHandler{}
+	handler := newSamplingHandler(recorder, SamplingConfig{
+		Levels:      map[slog.Level]SamplingPolicy{slog.LevelError: {First: 1}},
+		MaxMessages: 2,
+	})
+	defer handler.Close()
+
+	log := slog.New(handler)
+	for i := 0; i < 10; i++ {
+		log.Error(fmt.Sprintf("Order %d failed", i))
+	}
+
+	if len(handler.sampler.counters) != 3 {
+		t.Errorf("Expected 2 counters and the overflow bucket, got %d", len(handler.sampler.counters))
+	}
+
+	// Orders 0 and 1 are counted separately, the first of the others is logged
+	if len(recorder.records) != 3 {
+		t.Fatalf("Expected 3 records, got %d", len(recorder.records))
+	}
+
+	recorder.records = nil
+	if err := handler.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	if len(recorder.records) != 1 {
+		t.Fatalf("Expected one report, got %d records", len(recorder.records))
+	}
+	attrs := recordAttrs(recorder.records[0])
+	if attrs["sampled_message"].String() != overflowSampledMessage || attrs["suppressed"].Int64() != 7 {
+		t.Errorf("Unexpected report %v", attrs)
+	}
+}
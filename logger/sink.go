@@ -0,0 +1,167 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+)
+
+// Sink is one destination of a logger, with its own level, format and filter
+type Sink struct {
+	// Level is the minimum level written to the sink, nil writes every record enabled by the logger levels
+	Level slog.Leveler
+	// Filter drops the records for which it returns false, nil keeps every record
+	Filter func(ctx context.Context, record slog.Record) bool
+	// Format selects JSON (default), logfmt or colorized console output
+	Format Format
+	// Output is where the sink writes, stdout when both Output and File are nil
+	Output io.Writer
+	// File writes to a rotating file, alone or alongside Output
+	File *FileConfig
+	// Async writes from a background goroutine through a bounded buffer, nil writes synchronously
+	Async *AsyncConfig
+	// Handler replaces the built-in handlers; Format, Output, File and Async are ignored.
+	// A handler with Flush(ctx) error and Close() error methods, such as the NetworkHandler
+	// of NewSyslogHandler and NewHTTPHandler, is flushed and closed with the logger.
+	Handler slog.Handler
+}
+
+// newSinkHandler creates the handler writing to the sink, the opened outputs are added to outputs
+func newSinkHandler(sink Sink, opts *slog.HandlerOptions, outputs *outputs) slog.Handler {
+	handler := sink.Handler
+	if handler != nil {
+		if f, ok := handler.(flusher); ok {
+			outputs.handlers = append(outputs.handlers, f)
+		}
+		if closer, ok := handler.(io.Closer); ok {
+			outputs.closers = append(outputs.closers, closer)
+		}
+	} else {
+		output := sink.Output
+		if sink.File != nil {
+			file := NewFileWriter(*sink.File)
+			outputs.closers = append(outputs.closers, file)
+
+			if output == nil {
+				output = file
+			} else {
+				output = io.MultiWriter(output, file)
+			}
+		}
+		if output == nil {
+			output = os.Stdout
+		}
+
+		if sink.Async != nil {
+			async := NewAsyncWriter(output, *sink.Async)
+			outputs.asyncs = append(outputs.asyncs, async)
+			output = async
+		}
+
+		handler = newFormatHandler(sink.Format, output, opts)
+	}
+
+	if sink.Level == nil && sink.Filter == nil {
+		return handler
+	}
+
+	return &sinkHandler{handler: handler, level: sink.Level, filter: sink.Filter}
+}
+
+// sinkHandler applies the level and filter of a sink
+type sinkHandler struct {
+	handler slog.Handler
+	level   slog.Leveler
+	filter  func(ctx context.Context, record slog.Record) bool
+}
+
+func (h *sinkHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if h.level != nil && level < h.level.Level() {
+		return false
+	}
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *sinkHandler) Handle(ctx context.Context, record slog.Record) error {
+	if h.filter != nil && !h.filter(ctx, record) {
+		return nil
+	}
+	return h.handler.Handle(ctx, record)
+}
+
+func (h *sinkHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &sinkHandler{handler: h.handler.WithAttrs(attrs), level: h.level, filter: h.filter}
+}
+
+func (h *sinkHandler) WithGroup(name string) slog.Handler {
+	return &sinkHandler{handler: h.handler.WithGroup(name), level: h.level, filter: h.filter}
+}
+
+// fanoutHandler passes records to every enabled handler.
+// A handler that fails or panics does not prevent the others from writing the record.
+type fanoutHandler struct {
+	handlers []slog.Handler
+}
+
+func newFanoutHandler(handlers []slog.Handler) slog.Handler {
+	if len(handlers) == 1 {
+		return handlers[0]
+	}
+	return &fanoutHandler{handlers: handlers}
+}
+
+func (h *fanoutHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, handler := range h.handlers {
+		if handler.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *fanoutHandler) Handle(ctx context.Context, record slog.Record) error {
+	var errs []error
+
+	for _, handler := range h.handlers {
+		if !handler.Enabled(ctx, record.Level) {
+			continue
+		}
+
+		// Each handler gets its own copy, so that attributes added by one are not seen by the others
+		if err := handleSafely(ctx, handler, record.Clone()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (h *fanoutHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make([]slog.Handler, len(h.handlers))
+	for index, handler := range h.handlers {
+		handlers[index] = handler.WithAttrs(attrs)
+	}
+	return &fanoutHandler{handlers: handlers}
+}
+
+func (h *fanoutHandler) WithGroup(name string) slog.Handler {
+	handlers := make([]slog.Handler, len(h.handlers))
+	for index, handler := range h.handlers {
+		handlers[index] = handler.WithGroup(name)
+	}
+	return &fanoutHandler{handlers: handlers}
+}
+
+// handleSafely converts a panic of the handler into an error
+func handleSafely(ctx context.Context, handler slog.Handler, record slog.Record) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("log handler panicked: %v", r)
+		}
+	}()
+
+	return handler.Handle(ctx, record)
+}
@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// failingHandler fails or panics on every record
+type failingHandler struct {
+	panics bool
+}
+
+func (h *failingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *failingHandler) Handle(context.Context, slog.Record) error {
+	if h.panics {
+		panic("broken sink")
+	}
+	return errors.New("broken sink")
+}
+
+func (h *failingHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h *failingHandler) WithGroup(string) slog.Handler { return h }
+
+// TestLoggerSinks tests per-sink levels, formats and filters, and that attrs and groups reach every sink
+func TestLoggerSinks(t *testing.T) {
+	var stdout, file bytes.Buffer
+	errorSink := &recordingHandler{}
+
+	log := New(&Config{
+		Level: slog.LevelDebug,
+		Sinks: []Sink{
+			{Output: &stdout, Level: slog.LevelInfo},
+			{Output: &file, Format: FormatLogfmt, Level: slog.LevelWarn},
+			{Handler: errorSink, Level: slog.LevelError},
+			{Handler: &failingHandler{}},
+			{Handler: &failingHandler{panics: true}, Filter: func(_ context.Context, record slog.Record) bool {
+				return record.Message != "Skipped by filter"
+			}},
+		},
+	})
+
+	logger := slog.New(log.Handler()).With("service", "orders").WithGroup("order")
+	ctx := context.Background()
+
+	logger.DebugContext(ctx, "Debug only")
+	logger.InfoContext(ctx, "Order created", "id", 1)
+	logger.WarnContext(ctx, "Order delayed", "id", 2)
+	logger.ErrorContext(ctx, "Order failed", "id", 3)
+
+	if strings.Contains(stdout.String(), "Debug only") || strings.Count(stdout.String(), "\n") != 3 {
+		t.Errorf("Expected Info+ records on stdout, got %q", stdout.String())
+	}
+	if !strings.Contains(stdout.String(), `"service":"orders","order":{"id":1}`) {
+		t.Errorf("Expected attrs and groups on stdout, got %q", stdout.String())
+	}
+
+	if strings.Contains(file.String(), "Order created") || strings.Count(file.String(), "\n") != 2 {
+		t.Errorf("Expected Warn+ records in the file, got %q", file.String())
+	}
+	if !strings.Contains(file.String(), "service=orders order.id=2") {
+		t.Errorf("Expected logfmt attrs and groups in the file, got %q", file.String())
+	}
+
+	if len(errorSink.records) != 1 || errorSink.records[0].Message != "Order failed" {
+		t.Errorf("Expected only the Error record in the error sink, got %d records", len(errorSink.records))
+	}
+
+	// The panicking sink is skipped by its filter, the failing one does not affect the others
+	err := log.Handler().Handle(ctx, slog.NewRecord(errorSink.records[0].Time, slog.LevelInfo, "Skipped by filter", 0))
+	if err == nil || !strings.Contains(err.Error(), "broken sink") || strings.Contains(err.Error(), "panicked") {
+		t.Errorf("Expected the error of the failing sink only, got %v", err)
+	}
+
+	err = log.Handler().Handle(ctx, slog.NewRecord(errorSink.records[0].Time, slog.LevelInfo, "Recovered", 0))
+	if err == nil || !strings.Contains(err.Error(), "log handler panicked") {
+		t.Errorf("Expected the panic to be returned as an error, got %v", err)
+	}
+	if !strings.Contains(stdout.String(), "Recovered") {
+		t.Errorf("Expected the record on stdout despite the failing sinks")
+	}
+}
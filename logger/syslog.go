@@ -0,0 +1,181 @@
+package logger
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// FacilityUser is the syslog facility of user-level messages
+	FacilityUser = 1
+	// FacilityLocal0 is the first of the syslog facilities reserved for local use, local1 to local7 follow
+	FacilityLocal0 = 16
+
+	defaultDialTimeout = 5 * time.Second
+)
+
+// SyslogConfig configures a sink sending RFC 5424 messages to a syslog server
+type SyslogConfig struct {
+	// Network is "udp", "tcp" or "tls"
+	Network string
+	// Address is the host:port of the syslog server
+	Address string
+	// TLSConfig is used with the "tls" network
+	TLSConfig *tls.Config
+	// Facility is the syslog facility, FacilityUser by default
+	Facility int
+	// AppName is the APP-NAME of messages, the executable name by default
+	AppName string
+	// Hostname is the HOSTNAME of messages, os.Hostname by default
+	Hostname string
+	// DialTimeout bounds connection and write time, 5s by default
+	DialTimeout time.Duration
+	// Batching controls batching, retries and spilling
+	Batching NetworkConfig
+}
+
+// NewSyslogHandler creates a handler sending records to a syslog server over UDP, TCP or TLS.
+// The MSG part of each message is the JSON encoding of the record; TCP and TLS use octet-counting framing.
+func NewSyslogHandler(config SyslogConfig, opts *slog.HandlerOptions) (*NetworkHandler, error) {
+	switch config.Network {
+	case "udp", "tcp", "tls":
+	default:
+		return nil, fmt.Errorf("unsupported syslog network %q", config.Network)
+	}
+
+	if config.Facility == 0 {
+		config.Facility = FacilityUser
+	}
+	if config.AppName == "" {
+		config.AppName = filepath.Base(os.Args[0])
+	}
+	if config.Hostname == "" {
+		config.Hostname, _ = os.Hostname()
+	}
+	if config.DialTimeout <= 0 {
+		config.DialTimeout = defaultDialTimeout
+	}
+
+	header := fmt.Sprintf("%s %s %d - -", nilValue(config.Hostname), nilValue(config.AppName), os.Getpid())
+	frame := func(record slog.Record, message []byte) []byte {
+		priority := config.Facility*8 + syslogSeverity(record.Level)
+		timestamp := record.Time.UTC().Format(time.RFC3339Nano)
+		if record.Time.IsZero() {
+			timestamp = "-"
+		}
+
+		entry := make([]byte, 0, len(header)+len(message)+48)
+		entry = fmt.Appendf(entry, "<%d>1 %s %s ", priority, timestamp, header)
+		return append(entry, message...)
+	}
+
+	return newNetworkHandler(config.Batching, &syslogTransport{config: config}, opts, frame), nil
+}
+
+// syslogSeverity maps slog levels to syslog severities
+func syslogSeverity(level slog.Level) int {
+	switch {
+	case level >= slog.LevelError:
+		return 3 // error
+	case level >= slog.LevelWarn:
+		return 4 // warning
+	case level >= slog.LevelInfo:
+		return 6 // informational
+	default:
+		return 7 // debug
+	}
+}
+
+func nilValue(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// syslogTransport sends messages over a connection that is dialed on first use and after failures
+type syslogTransport struct {
+	config SyslogConfig
+	mu     sync.Mutex
+	conn   net.Conn
+}
+
+func (t *syslogTransport) send(_ context.Context, batch [][]byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.conn == nil {
+		conn, err := t.dial()
+		if err != nil {
+			return err
+		}
+		t.conn = conn
+	}
+
+	if err := t.conn.SetWriteDeadline(time.Now().Add(t.config.DialTimeout)); err != nil {
+		return t.fail(err)
+	}
+
+	// UDP sends one message per datagram
+	if t.config.Network == "udp" {
+		for _, entry := range batch {
+			if _, err := t.conn.Write(entry); err != nil {
+				return t.fail(err)
+			}
+		}
+		return nil
+	}
+
+	var payload []byte
+	for _, entry := range batch {
+		payload = strconv.AppendInt(payload, int64(len(entry)), 10)
+		payload = append(payload, ' ')
+		payload = append(payload, entry...)
+	}
+
+	if _, err := t.conn.Write(payload); err != nil {
+		return t.fail(err)
+	}
+	return nil
+}
+
+func (t *syslogTransport) dial() (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: t.config.DialTimeout}
+
+	if t.config.Network == "tls" {
+		return tls.DialWithDialer(dialer, "tcp", t.config.Address, t.config.TLSConfig)
+	}
+	return dialer.Dial(t.config.Network, t.config.Address)
+}
+
+// fail closes the connection so that the next send dials again
+func (t *syslogTransport) fail(err error) error {
+	_ = t.conn.Close()
+	t.conn = nil
+	return err
+}
+
+func (t *syslogTransport) close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.conn == nil {
+		return nil
+	}
+
+	err := t.conn.Close()
+	t.conn = nil
+	if errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+	return err
+}
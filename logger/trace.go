@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"context"
+
+	pkgContext "github.com/duongptryu/gox/context"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultTraceIDKey = "trace_id"
+	defaultSpanIDKey  = "span_id"
+)
+
+// TraceConfig names the fields correlating records with the active trace
+type TraceConfig struct {
+	// TraceIDKey is the name of the trace ID field, "trace_id" when empty
+	TraceIDKey string
+	// SpanIDKey is the name of the span ID field, "span_id" when empty
+	SpanIDKey string
+}
+
+// extractTraceFields appends the IDs of the active OpenTelemetry span,
+// or of the W3C traceparent stored in ctx when there is no span
+func extractTraceFields(ctx context.Context, config TraceConfig, fields []*Field) []*Field {
+	var traceID, spanID string
+
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		traceID, spanID = spanContext.TraceID().String(), spanContext.SpanID().String()
+	} else if traceParent := pkgContext.GetTraceParent(ctx); traceParent != "" {
+		traceID, spanID, _ = pkgContext.ParseTraceParent(traceParent)
+	}
+
+	if traceID == "" {
+		return fields
+	}
+
+	traceIDKey, spanIDKey := config.TraceIDKey, config.SpanIDKey
+	if traceIDKey == "" {
+		traceIDKey = defaultTraceIDKey
+	}
+	if spanIDKey == "" {
+		spanIDKey = defaultSpanIDKey
+	}
+
+	return append(fields, F(traceIDKey, traceID), F(spanIDKey, spanID))
+}
@@ -0,0 +1,97 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	pkgContext "github.com/duongptryu/gox/context"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TestTraceFields tests trace correlation from OpenTelemetry spans and traceparent headers
+func TestTraceFields(t *testing.T) {
+	t.Parallel()
+
+	traceParent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x0a, 0xf7, 0x65, 0x19, 0x16, 0xcd, 0x43, 0xdd, 0x84, 0x48, 0xeb, 0x21, 0x1c, 0x80, 0x31, 0x9c},
+		SpanID:  trace.SpanID{0xb7, 0xad, 0x6b, 0x71, 0x69, 0x20, 0x33, 0x31},
+	})
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		config   TraceConfig
+		expected map[string]any
+	}{
+		{
+			name:     "traceparent",
+			ctx:      pkgContext.WithTraceParent(context.Background(), traceParent),
+			expected: map[string]any{"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736", "span_id": "00f067aa0ba902b7"},
+		},
+		{
+			name: "span takes precedence",
+			ctx:  trace.ContextWithSpanContext(pkgContext.WithTraceParent(context.Background(), traceParent), spanContext),
+			expected: map[string]any{
+				"trace_id": "0af7651916cd43dd8448eb211c80319c",
+				"span_id":  "b7ad6b7169203331",
+			},
+		},
+		{
+			name:     "custom keys",
+			ctx:      pkgContext.WithTraceParent(context.Background(), traceParent),
+			config:   TraceConfig{TraceIDKey: "dd.trace_id", SpanIDKey: "dd.span_id"},
+			expected: map[string]any{"dd.trace_id": "4bf92f3577b34da6a3ce929d0e0e4736", "trace_id": nil},
+		},
+		{
+			name:     "invalid traceparent",
+			ctx:      pkgContext.WithTraceParent(context.Background(), "00-00000000000000000000000000000000-00f067aa0ba902b7-01"),
+			expected: map[string]any{"trace_id": nil, "span_id": nil},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buffer bytes.Buffer
+			New(&Config{Output: &buffer, Trace: test.config}).Info(test.ctx, "Order created")
+
+			record := decodeRecord(t, bytes.TrimSpace(buffer.Bytes()))
+			for key, value := range test.expected {
+				if record[key] != value {
+					t.Errorf("Expected %s=%v, got %v", key, value, record[key])
+				}
+			}
+		})
+	}
+}
+
+// TestParseTraceParent tests the W3C traceparent format checks
+func TestParseTraceParent(t *testing.T) {
+	t.Parallel()
+
+	valid := []string{
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00-future",
+	}
+	invalid := []string{
+		"",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		strings.Repeat("0", 55),
+	}
+
+	for _, value := range valid {
+		if _, _, ok := pkgContext.ParseTraceParent(value); !ok {
+			t.Errorf("Expected %q to be valid", value)
+		}
+	}
+	for _, value := range invalid {
+		if _, _, ok := pkgContext.ParseTraceParent(value); ok {
+			t.Errorf("Expected %q to be invalid", value)
+		}
+	}
+}
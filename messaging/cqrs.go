@@ -12,6 +12,7 @@ import (
 	"github.com/ThreeDotsLabs/watermill/message/router/middleware"
 	"github.com/ThreeDotsLabs/watermill/message/router/plugin"
 	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/shutdown"
 )
 
 // Config holds configuration for the event bus.
@@ -34,10 +35,6 @@ type cqrsBus struct {
 
 // NewBus creates a new CQRS event bus.
 func NewBus(cfg Config) (*cqrsBus, error) {
-	if cfg.Logger == nil {
-		cfg.Logger = slog.Default()
-	}
-
 	generateEventTopic := func(eventName string) string {
 		return fmt.Sprintf("events.%s", eventName)
 	}
@@ -46,7 +43,11 @@ func NewBus(cfg Config) (*cqrsBus, error) {
 		return fmt.Sprintf("commands.%s", commandName)
 	}
 
-	wmLogger := watermill.NewSlogLogger(cfg.Logger)
+	var wmLogger watermill.LoggerAdapter = busWatermillLogger{}
+	if cfg.Logger != nil {
+		wmLogger = watermill.NewSlogLogger(cfg.Logger)
+	}
+
 	marshaler := cqrs.JSONMarshaler{
 		GenerateName: cqrs.StructName,
 	}
@@ -56,9 +57,11 @@ func NewBus(cfg Config) (*cqrsBus, error) {
 		return nil, err
 	}
 
-	retryMiddleware := middleware.Retry{
-		MaxRetries:      3,
-		InitialInterval: time.Millisecond * 10,
+	retryMiddleware := retry{
+		maxRetries:      3,
+		initialInterval: time.Millisecond * 10,
+		maxInterval:     time.Second,
+		multiplier:      2,
 	}
 
 	poisonQueue, err := middleware.PoisonQueue(cfg.Publisher, "poison_queue")
@@ -83,7 +86,7 @@ func NewBus(cfg Config) (*cqrsBus, error) {
 		Marshaler: marshaler,
 		Logger:    wmLogger,
 		OnSend: func(params cqrs.CommandBusOnSendParams) error {
-			logger.Info(params.Message.Context(), "Sending command", logger.F("command_name", params.CommandName))
+			busLogger().Info(params.Message.Context(), "Sending command", logger.F("command_name", params.CommandName))
 			params.Message.Metadata.Set("sent_at", time.Now().String())
 			return nil
 		},
@@ -99,7 +102,7 @@ func NewBus(cfg Config) (*cqrsBus, error) {
 		Marshaler: marshaler,
 		Logger:    wmLogger,
 		OnPublish: func(params cqrs.OnEventSendParams) error {
-			logger.Info(params.Message.Context(), "Publishing event", logger.F("event_name", params.EventName))
+			busLogger().Info(params.Message.Context(), "Publishing event", logger.F("event_name", params.EventName))
 			params.Message.Metadata.Set("published_at", time.Now().String())
 			return nil
 		},
@@ -120,14 +123,18 @@ func NewBus(cfg Config) (*cqrsBus, error) {
 		OnHandle: func(params cqrs.CommandProcessorOnHandleParams) error {
 			start := time.Now()
 
-			err := params.Handler.Handle(params.Message.Context(), params.Command)
+			// Logs of the handler carry the command name
+			ctx := logger.WithFields(params.Message.Context(), logger.F("command_name", params.CommandName))
 
-			logger.Info(params.Message.Context(), "Command handled",
-				logger.F("command_name", params.CommandName),
+			err := params.Handler.Handle(ctx, params.Command)
+
+			busLogger().Info(ctx, "Command handled",
 				logger.F("duration", time.Since(start)),
 				logger.F("err", err),
 			)
 
+			SetErrorMetadata(params.Message, err)
+
 			return err
 		},
 	})
@@ -147,14 +154,18 @@ func NewBus(cfg Config) (*cqrsBus, error) {
 		OnHandle: func(params cqrs.EventProcessorOnHandleParams) error {
 			start := time.Now()
 
-			err := params.Handler.Handle(params.Message.Context(), params.Event)
+			// Logs of the handler carry the event name
+			ctx := logger.WithFields(params.Message.Context(), logger.F("event_name", params.EventName))
+
+			err := params.Handler.Handle(ctx, params.Event)
 
-			logger.Info(params.Message.Context(), "Event handled",
-				logger.F("event_name", params.EventName),
+			busLogger().Info(ctx, "Event handled",
 				logger.F("duration", time.Since(start)),
 				logger.F("err", err),
 			)
 
+			SetErrorMetadata(params.Message, err)
+
 			return err
 		},
 	})
@@ -162,7 +173,7 @@ func NewBus(cfg Config) (*cqrsBus, error) {
 		return nil, err
 	}
 
-	return &cqrsBus{
+	bus := &cqrsBus{
 		commandBus:       commandBus,
 		eventBus:         eventBus,
 		commandProcessor: commandProcessor,
@@ -170,7 +181,12 @@ func NewBus(cfg Config) (*cqrsBus, error) {
 		router:           router,
 		logger:           cfg.Logger,
 		marshaler:        marshaler,
-	}, nil
+	}
+
+	// In-flight messages are completed before the resources registered earlier are released
+	shutdown.RegisterCloser("messaging", bus)
+
+	return bus, nil
 }
 
 func (b *cqrsBus) GetCommandBus() CommandBus {
@@ -218,3 +234,8 @@ func (b *cqrsBus) RegisterEventHandler(eventName string, handler EventHandler) e
 func (b *cqrsBus) Run(ctx context.Context) error {
 	return b.router.Run(ctx)
 }
+
+// Close stops the router, waiting for the messages being handled
+func (b *cqrsBus) Close() error {
+	return b.router.Close()
+}
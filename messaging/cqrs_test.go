@@ -0,0 +1,33 @@
+package messaging
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/pubsub/gochannel"
+	"github.com/duongptryu/gox/logger/loggertest"
+)
+
+type createOrder struct {
+	ID int
+}
+
+// TestNewBusLogsWithDefaultLogger tests that a bus created before the default logger is set logs through it
+func TestNewBusLogsWithDefaultLogger(t *testing.T) {
+	pubSub := gochannel.NewGoChannel(gochannel.Config{}, watermill.NopLogger{})
+	defer pubSub.Close()
+
+	bus, err := NewBus(Config{Publisher: pubSub, Subscriber: pubSub})
+	if err != nil {
+		t.Fatalf("Failed to create bus: %v", err)
+	}
+
+	recorder := loggertest.Install(t)
+
+	if err := bus.PublishCommand(context.Background(), &createOrder{ID: 1}); err != nil {
+		t.Fatalf("Failed to publish command: %v", err)
+	}
+
+	recorder.Expect(loggertest.Message("Sending command"), loggertest.Attr("logger", "messaging"))
+}
@@ -0,0 +1,42 @@
+package messaging
+
+import (
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/duongptryu/gox/syserr"
+)
+
+// ErrorMetadataKey is the message metadata key holding the encoded handler error
+const ErrorMetadataKey = "error"
+
+// SetErrorMetadata encodes the error into the message metadata so that it survives
+// poison queues and other process boundaries. A nil error removes the error of a previous
+// attempt, so that a message handled successfully on retry does not carry it.
+func SetErrorMetadata(msg *message.Message, err error) {
+	if err == nil {
+		delete(msg.Metadata, ErrorMetadataKey)
+		return
+	}
+
+	encoded, encodeErr := syserr.Encode(err, true)
+	if encodeErr != nil {
+		delete(msg.Metadata, ErrorMetadataKey)
+		return
+	}
+
+	msg.Metadata.Set(ErrorMetadataKey, string(encoded))
+}
+
+// GetErrorFromMetadata decodes the error stored by SetErrorMetadata, or returns nil if there is none
+func GetErrorFromMetadata(msg *message.Message) *syserr.Error {
+	encoded := msg.Metadata.Get(ErrorMetadataKey)
+	if encoded == "" {
+		return nil
+	}
+
+	decoded, err := syserr.Decode([]byte(encoded))
+	if err != nil {
+		return nil
+	}
+
+	return decoded
+}
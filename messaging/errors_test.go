@@ -0,0 +1,29 @@
+package messaging
+
+import (
+	"testing"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/duongptryu/gox/syserr"
+)
+
+// TestSetErrorMetadata tests that the error of a failed attempt is cleared by a successful one
+func TestSetErrorMetadata(t *testing.T) {
+	msg := message.NewMessage("1", nil)
+
+	SetErrorMetadata(msg, syserr.New(syserr.InternalCode, "timeout"))
+
+	decoded := GetErrorFromMetadata(msg)
+	if decoded == nil {
+		t.Fatal("Expected the error to be stored in the metadata")
+	}
+	if decoded.Code() != syserr.InternalCode {
+		t.Errorf("Expected code %s, got %s", syserr.InternalCode, decoded.Code())
+	}
+
+	SetErrorMetadata(msg, nil)
+
+	if _, ok := msg.Metadata[ErrorMetadataKey]; ok {
+		t.Errorf("Expected the error to be removed, got %v", GetErrorFromMetadata(msg))
+	}
+}
@@ -0,0 +1,41 @@
+package messaging
+
+import (
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/duongptryu/gox/logger"
+)
+
+// busLogger returns the logger of the bus. It is resolved when logging rather than in NewBus,
+// so that a bus created before logger.Init logs through the configured default logger.
+func busLogger() *logger.Logger {
+	return logger.Named("messaging")
+}
+
+// busWatermillLogger passes the Watermill logs to busLogger, it is used when Config.Logger is nil
+type busWatermillLogger struct {
+	fields watermill.LogFields
+}
+
+func (l busWatermillLogger) adapter() watermill.LoggerAdapter {
+	return watermill.NewSlogLogger(busLogger().Slog()).With(l.fields)
+}
+
+func (l busWatermillLogger) Error(msg string, err error, fields watermill.LogFields) {
+	l.adapter().Error(msg, err, fields)
+}
+
+func (l busWatermillLogger) Info(msg string, fields watermill.LogFields) {
+	l.adapter().Info(msg, fields)
+}
+
+func (l busWatermillLogger) Debug(msg string, fields watermill.LogFields) {
+	l.adapter().Debug(msg, fields)
+}
+
+func (l busWatermillLogger) Trace(msg string, fields watermill.LogFields) {
+	l.adapter().Trace(msg, fields)
+}
+
+func (l busWatermillLogger) With(fields watermill.LogFields) watermill.LoggerAdapter {
+	return busWatermillLogger{fields: l.fields.Add(fields)}
+}
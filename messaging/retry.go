@@ -0,0 +1,103 @@
+package messaging
+
+import (
+	"context"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/syserr"
+)
+
+// retry retries failed handlers with exponential backoff.
+// Permanent errors are returned immediately, and the retry-after hint of an error is waited for
+// when it is longer than the backoff interval, up to the maximum interval and the message deadline.
+type retry struct {
+	// log is the logger of the retries, nil logs through the bus logger
+	log             *logger.Logger
+	maxRetries      int
+	initialInterval time.Duration
+	maxInterval     time.Duration
+	multiplier      float64
+}
+
+// Middleware returns the retry middleware
+func (r retry) Middleware(h message.HandlerFunc) message.HandlerFunc {
+	return func(msg *message.Message) ([]*message.Message, error) {
+		producedMessages, err := h(msg)
+		if err == nil || !syserr.IsRetryable(err) {
+			return producedMessages, err
+		}
+
+		ctx := msg.Context()
+		log := r.logger()
+		interval := r.initialInterval
+
+		for retryNum := 1; retryNum <= r.maxRetries; retryNum++ {
+			waitTime := r.waitTime(ctx, interval, err)
+
+			log.Warning(ctx, "Handler failed, retrying",
+				logger.F("retry_no", retryNum),
+				logger.F("max_retries", r.maxRetries),
+				logger.F("wait_time", waitTime),
+				logger.F("err", err),
+			)
+
+			select {
+			case <-ctx.Done():
+				return producedMessages, err
+			case <-time.After(waitTime):
+			}
+
+			producedMessages, err = h(msg)
+			if err == nil {
+				return producedMessages, nil
+			}
+			if !syserr.IsRetryable(err) {
+				return producedMessages, err
+			}
+
+			interval = r.nextInterval(interval)
+		}
+
+		log.Error(ctx, "Handler failed, giving up",
+			logger.F("max_retries", r.maxRetries),
+			logger.F("err", err),
+		)
+
+		return nil, err
+	}
+}
+
+func (r retry) logger() *logger.Logger {
+	if r.log != nil {
+		return r.log
+	}
+	return busLogger()
+}
+
+// waitTime returns the backoff interval, or the retry-after hint of err when it is longer.
+// The hint is capped at the maximum interval, and the wait at the time left before the ctx deadline.
+func (r retry) waitTime(ctx context.Context, interval time.Duration, err error) time.Duration {
+	waitTime := interval
+	if retryAfter := syserr.GetRetryAfterFromGenericError(err); retryAfter > waitTime {
+		if r.maxInterval > 0 {
+			retryAfter = min(retryAfter, r.maxInterval)
+		}
+		waitTime = max(waitTime, retryAfter)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		waitTime = max(min(waitTime, time.Until(deadline)), 0)
+	}
+
+	return waitTime
+}
+
+func (r retry) nextInterval(current time.Duration) time.Duration {
+	next := time.Duration(float64(current) * r.multiplier)
+	if r.maxInterval > 0 && next > r.maxInterval {
+		return r.maxInterval
+	}
+	return next
+}
@@ -0,0 +1,114 @@
+package messaging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/logger/loggertest"
+	"github.com/duongptryu/gox/syserr"
+)
+
+func newCountingHandler(err error, calls *int) message.HandlerFunc {
+	return func(msg *message.Message) ([]*message.Message, error) {
+		*calls++
+		return nil, err
+	}
+}
+
+// TestRetryClassification tests that only retryable errors are retried
+func TestRetryClassification(t *testing.T) {
+	log := logger.New(&logger.Config{Level: slog.LevelInfo, Output: io.Discard})
+	r := retry{log: log, maxRetries: 3, initialInterval: time.Millisecond, maxInterval: time.Millisecond, multiplier: 2}
+
+	testCases := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"validation", syserr.New(syserr.ValidationCode, "invalid"), 1},
+		{"internal", syserr.New(syserr.InternalCode, "timeout"), 4},
+		{"marked permanent", syserr.New(syserr.InternalCode, "corrupt payload").WithRetryable(false), 1},
+		{"marked retryable", syserr.New(syserr.ConflictCode, "version mismatch").WithRetryable(true), 4},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			calls := 0
+			_, err := r.Middleware(newCountingHandler(testCase.err, &calls))(message.NewMessage("1", nil))
+
+			if err == nil {
+				t.Fatal("Expected the handler error to be returned")
+			}
+			if calls != testCase.expected {
+				t.Errorf("Expected %d calls, got %d", testCase.expected, calls)
+			}
+		})
+	}
+}
+
+// TestRetryAfter tests that the retry-after hint is honored when longer than the backoff
+func TestRetryAfter(t *testing.T) {
+	r := retry{maxRetries: 3, initialInterval: time.Millisecond, maxInterval: time.Second, multiplier: 2}
+	ctx := context.Background()
+
+	if wait := r.waitTime(ctx, time.Millisecond, syserr.New(syserr.InternalCode, "busy").WithRetryAfter(50*time.Millisecond)); wait != 50*time.Millisecond {
+		t.Errorf("Expected the retry-after hint to be used, got %v", wait)
+	}
+	if wait := r.waitTime(ctx, time.Second, syserr.New(syserr.InternalCode, "busy").WithRetryAfter(50*time.Millisecond)); wait != time.Second {
+		t.Errorf("Expected the backoff interval to be used, got %v", wait)
+	}
+	if wait := r.waitTime(ctx, time.Millisecond, syserr.New(syserr.InternalCode, "busy")); wait != time.Millisecond {
+		t.Errorf("Expected the backoff interval to be used, got %v", wait)
+	}
+	if wait := r.waitTime(ctx, time.Millisecond, syserr.New(syserr.InternalCode, "busy").WithRetryAfter(time.Hour)); wait != time.Second {
+		t.Errorf("Expected the retry-after hint to be capped at the maximum interval, got %v", wait)
+	}
+
+	deadlineCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+
+	if wait := r.waitTime(deadlineCtx, time.Millisecond, syserr.New(syserr.InternalCode, "busy").WithRetryAfter(time.Hour)); wait > 100*time.Millisecond {
+		t.Errorf("Expected the wait to end before the deadline, got %v", wait)
+	}
+}
+
+// TestRetryContextDone tests that the wait ends when the message context is done
+func TestRetryContextDone(t *testing.T) {
+	r := retry{log: loggertest.New(t).Logger(), maxRetries: 3, initialInterval: time.Hour, maxInterval: time.Hour, multiplier: 2}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	msg := message.NewMessage("1", nil)
+	msg.SetContext(ctx)
+
+	calls := 0
+	if _, err := r.Middleware(newCountingHandler(syserr.New(syserr.InternalCode, "timeout"), &calls))(msg); err == nil {
+		t.Fatal("Expected the handler error to be returned")
+	}
+	if calls != 1 {
+		t.Errorf("Expected 1 call, got %d", calls)
+	}
+}
+
+// TestRetryLogs tests that retries are logged with the handler error
+func TestRetryLogs(t *testing.T) {
+	recorder := loggertest.New(t)
+	r := retry{log: recorder.Logger(), maxRetries: 2, initialInterval: time.Millisecond, maxInterval: time.Millisecond, multiplier: 2}
+
+	calls := 0
+	_, _ = r.Middleware(newCountingHandler(syserr.New(syserr.InternalCode, "timeout"), &calls))(message.NewMessage("1", nil))
+
+	recorder.Expect(loggertest.Level(slog.LevelWarn), loggertest.Message("Handler failed, retrying"),
+		loggertest.Attr("retry_no", 1), loggertest.Code(syserr.InternalCode))
+	recorder.ExpectNone(loggertest.Attr("retry_no", 3))
+
+	if records := recorder.Find(loggertest.Message("Handler failed, giving up")); len(records) != 1 || records[0].Level != slog.LevelError {
+		t.Errorf("Expected one error record when giving up, got %v", records)
+	}
+}
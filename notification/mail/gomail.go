@@ -367,23 +367,23 @@ func (g *goMailProvider) addAttachment(msg *gomail.Message, attachment Attachmen
 // validateEmailMessage validates the email message structure
 func (g *goMailProvider) validateEmailMessage(message *EmailMessage) error {
 	if message == nil {
-		return syserr.New(syserr.ValidationCode, "email message cannot be nil")
+		return syserr.NewPublic(syserr.ValidationCode, "email message cannot be nil")
 	}
 
 	if message.From.Email == "" {
-		return syserr.New(syserr.ValidationCode, "from email is required")
+		return syserr.NewPublic(syserr.ValidationCode, "from email is required")
 	}
 
 	if len(message.To) == 0 {
-		return syserr.New(syserr.ValidationCode, "at least one recipient is required")
+		return syserr.NewPublic(syserr.ValidationCode, "at least one recipient is required")
 	}
 
 	if message.Subject == "" {
-		return syserr.New(syserr.ValidationCode, "subject is required")
+		return syserr.NewPublic(syserr.ValidationCode, "subject is required")
 	}
 
 	if message.TextBody == "" && message.HTMLBody == "" {
-		return syserr.New(syserr.ValidationCode, "either text body or HTML body is required")
+		return syserr.NewPublic(syserr.ValidationCode, "either text body or HTML body is required")
 	}
 
 	return nil
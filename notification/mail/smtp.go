@@ -57,7 +57,7 @@ func (s *smtpProvider) SendEmail(ctx context.Context, message *EmailMessage) (*S
 	// Get all recipients
 	recipients := s.getAllRecipients(message)
 	if len(recipients) == 0 {
-		return nil, syserr.New(syserr.ValidationCode, "no recipients specified")
+		return nil, syserr.NewPublic(syserr.ValidationCode, "no recipients specified")
 	}
 
 	// Send via SMTP
@@ -155,33 +155,33 @@ func (s *smtpProvider) Close() error {
 // validateEmailMessage validates the email message structure
 func (s *smtpProvider) validateEmailMessage(message *EmailMessage) error {
 	if message == nil {
-		return syserr.New(syserr.ValidationCode, "email message cannot be nil")
+		return syserr.NewPublic(syserr.ValidationCode, "email message cannot be nil")
 	}
 
 	if message.From.Email == "" {
-		return syserr.New(syserr.ValidationCode, "from email is required")
+		return syserr.NewPublic(syserr.ValidationCode, "from email is required")
 	}
 
 	if !s.isValidEmailFormat(message.From.Email) {
-		return syserr.New(syserr.ValidationCode, "invalid from email format")
+		return syserr.NewPublic(syserr.ValidationCode, "invalid from email format")
 	}
 
 	if len(message.To) == 0 {
-		return syserr.New(syserr.ValidationCode, "at least one recipient is required")
+		return syserr.NewPublic(syserr.ValidationCode, "at least one recipient is required")
 	}
 
 	for _, to := range message.To {
 		if !s.isValidEmailFormat(to.Email) {
-			return syserr.New(syserr.ValidationCode, "invalid recipient email format", syserr.F("email", to.Email))
+			return syserr.NewPublic(syserr.ValidationCode, "invalid recipient email format", syserr.F("email", to.Email))
 		}
 	}
 
 	if message.Subject == "" {
-		return syserr.New(syserr.ValidationCode, "subject is required")
+		return syserr.NewPublic(syserr.ValidationCode, "subject is required")
 	}
 
 	if message.TextBody == "" && message.HTMLBody == "" {
-		return syserr.New(syserr.ValidationCode, "either text body or HTML body is required")
+		return syserr.NewPublic(syserr.ValidationCode, "either text body or HTML body is required")
 	}
 
 	return nil
@@ -0,0 +1,200 @@
+package redact
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Placeholder replaces values that are fully masked
+const Placeholder = "[REDACTED]"
+
+const (
+	// maxCachedKeyLength is the length above which keys are matched without caching, long keys are usually dynamic
+	maxCachedKeyLength = 64
+	// maxCachedKeys bounds the number of cached keys, keys seen once the cache is full are matched without caching
+	maxCachedKeys = 1024
+)
+
+// Redactor is implemented by values that know how to redact themselves.
+// Redact is called for every logged or extracted value, regardless of its key.
+type Redactor interface {
+	Redact() string
+}
+
+// Redacted is a value that was already redacted and is left untouched by policies
+type Redacted string
+
+// Secret is a string that is always redacted in logs, error fields and fmt output
+type Secret string
+
+func (s Secret) Redact() string {
+	return Placeholder
+}
+
+func (s Secret) String() string {
+	return Placeholder
+}
+
+// Mask converts a sensitive value to its redacted form
+type Mask func(value string) string
+
+// MaskAll replaces the whole value with the placeholder
+func MaskAll(string) string {
+	return Placeholder
+}
+
+// MaskLast keeps the last n characters of the value, e.g. "************4242".
+// Values not longer than n characters are fully masked.
+func MaskLast(n int) Mask {
+	return func(value string) string {
+		runes := []rune(value)
+		if len(runes) <= n {
+			return Placeholder
+		}
+		return strings.Repeat("*", len(runes)-n) + string(runes[len(runes)-n:])
+	}
+}
+
+// Rule masks the values of keys matching a pattern
+type Rule struct {
+	Pattern *regexp.Regexp
+	Mask    Mask
+}
+
+// KeyRule creates a rule for keys matching the case-insensitive regular expression pattern.
+// It panics if the pattern is invalid.
+func KeyRule(pattern string, mask Mask) Rule {
+	return Rule{
+		Pattern: regexp.MustCompile("(?i)" + pattern),
+		Mask:    mask,
+	}
+}
+
+// Policy decides which values are redacted and how
+type Policy struct {
+	rules []Rule
+	// matches caches the rule index matched by each key, -1 when no rule matches
+	matches sync.Map
+	// cachedKeys counts the entries of matches
+	cachedKeys atomic.Int64
+}
+
+// NewPolicy creates a policy applying the first matching rule to each key.
+// A policy without rules only redacts Redactor values.
+func NewPolicy(rules ...Rule) *Policy {
+	return &Policy{rules: rules}
+}
+
+// DefaultPolicy masks passwords, secrets, tokens, API keys, credentials and cookies,
+// and keeps the last 4 digits of card and account numbers
+func DefaultPolicy() *Policy {
+	return NewPolicy(
+		KeyRule(`passw(or)?d|pwd|secret|token|api[_-]?key|authorization|cookie|credential|private[_-]?key`, MaskAll),
+		KeyRule(`card[_-]?number|(^|[_-])pan$|account[_-]?number|iban`, MaskLast(4)),
+	)
+}
+
+// Value returns the redacted form of the value stored under key.
+// Maps with string keys are redacted recursively.
+func (p *Policy) Value(key string, value any) any {
+	switch typedValue := value.(type) {
+	case nil, Redacted:
+		return value
+	case Redactor:
+		return Redacted(typedValue.Redact())
+	case map[string]any:
+		return p.redactMap(typedValue)
+	case map[string]string:
+		result := make(map[string]any, len(typedValue))
+		for mapKey, mapValue := range typedValue {
+			result[mapKey] = p.Value(mapKey, mapValue)
+		}
+		return result
+	}
+
+	rule := p.match(key)
+	if rule == nil {
+		return value
+	}
+
+	return Redacted(rule.Mask(fmt.Sprint(value)))
+}
+
+// ReplaceAttr redacts attributes; it can be used as slog.HandlerOptions.ReplaceAttr
+func (p *Policy) ReplaceAttr(_ []string, attr slog.Attr) slog.Attr {
+	if attr.Value.Kind() == slog.KindGroup {
+		return attr
+	}
+
+	value := attr.Value.Resolve()
+	redacted := p.Value(attr.Key, value.Any())
+
+	if redacted, ok := redacted.(Redacted); ok {
+		return slog.String(attr.Key, string(redacted))
+	}
+	if redactedMap, ok := redacted.(map[string]any); ok {
+		return slog.Any(attr.Key, redactedMap)
+	}
+
+	return attr
+}
+
+func (p *Policy) redactMap(values map[string]any) map[string]any {
+	result := make(map[string]any, len(values))
+	for key, value := range values {
+		result[key] = p.Value(key, value)
+	}
+	return result
+}
+
+func (p *Policy) match(key string) *Rule {
+	if cached, ok := p.matches.Load(key); ok {
+		return p.ruleAt(cached.(int))
+	}
+
+	index := -1
+	for ruleIndex, rule := range p.rules {
+		if rule.Pattern.MatchString(key) {
+			index = ruleIndex
+			break
+		}
+	}
+
+	if len(key) <= maxCachedKeyLength && p.cachedKeys.Load() < maxCachedKeys {
+		if _, loaded := p.matches.LoadOrStore(key, index); !loaded {
+			p.cachedKeys.Add(1)
+		}
+	}
+
+	return p.ruleAt(index)
+}
+
+func (p *Policy) ruleAt(index int) *Rule {
+	if index < 0 {
+		return nil
+	}
+	return &p.rules[index]
+}
+
+var policy atomic.Pointer[Policy]
+
+func init() {
+	policy.Store(DefaultPolicy())
+}
+
+// SetPolicy sets the policy used by syserr field extraction and the logger. Nil restores the default policy.
+func SetPolicy(p *Policy) {
+	if p == nil {
+		p = DefaultPolicy()
+	}
+	policy.Store(p)
+}
+
+// GetPolicy returns the current policy
+func GetPolicy() *Policy {
+	return policy.Load()
+}
@@ -0,0 +1,135 @@
+package redact
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+type apiKey string
+
+func (k apiKey) Redact() string {
+	return MaskLast(2)(string(k))
+}
+
+// TestDefaultPolicy tests the keys masked by the default policy
+func TestDefaultPolicy(t *testing.T) {
+	policy := DefaultPolicy()
+
+	testCases := []struct {
+		key      string
+		value    any
+		expected any
+	}{
+		{"password", "hunter2", Redacted(Placeholder)},
+		{"new_Password", "hunter2", Redacted(Placeholder)},
+		{"access_token", "abc", Redacted(Placeholder)},
+		{"X-Api-Key", "abc", Redacted(Placeholder)},
+		{"Authorization", "Bearer abc", Redacted(Placeholder)},
+		{"card_number", "4242424242424242", Redacted("************4242")},
+		{"card_number", 4242, Redacted(Placeholder)},
+		{"pan", "4242424242424242", Redacted("************4242")},
+		{"card_pan", "4242424242424242", Redacted("************4242")},
+		{"span", "db.query", "db.query"},
+		{"timespan", "5m", "5m"},
+		{"lifespan", "1h", "1h"},
+		{"user_id", 42, 42},
+		{"email", "a@b.c", "a@b.c"},
+		{"anything", apiKey("sk_live_1234"), Redacted("**********34")},
+		{"anything", Secret("value"), Redacted(Placeholder)},
+		{"password", nil, nil},
+	}
+
+	for _, testCase := range testCases {
+		if actual := policy.Value(testCase.key, testCase.value); actual != testCase.expected {
+			t.Errorf("Expected %#v for %q, got %#v", testCase.expected, testCase.key, actual)
+		}
+	}
+}
+
+// TestPolicyNestedMaps tests that maps are redacted recursively
+func TestPolicyNestedMaps(t *testing.T) {
+	value := DefaultPolicy().Value("payload", map[string]any{
+		"name":    "alice",
+		"secrets": map[string]string{"token": "abc"},
+	}).(map[string]any)
+
+	if value["name"] != "alice" {
+		t.Errorf("Expected name to be kept, got %v", value["name"])
+	}
+	if nested := value["secrets"].(map[string]any); nested["token"] != Redacted(Placeholder) {
+		t.Errorf("Expected nested token to be redacted, got %v", nested["token"])
+	}
+}
+
+// TestRedactedValuesAreNotMaskedTwice tests that already redacted values are left untouched
+func TestRedactedValuesAreNotMaskedTwice(t *testing.T) {
+	policy := DefaultPolicy()
+
+	once := policy.Value("card_number", "4242424242424242")
+	if twice := policy.Value("card_number", once); twice != once {
+		t.Errorf("Expected %v, got %v", once, twice)
+	}
+}
+
+// TestCustomPolicy tests custom rules and the empty policy
+func TestCustomPolicy(t *testing.T) {
+	policy := NewPolicy(KeyRule(`^ssn$`, MaskLast(4)))
+
+	if actual := policy.Value("ssn", "123-45-6789"); actual != Redacted("*******6789") {
+		t.Errorf("Expected ssn to be masked, got %v", actual)
+	}
+	if actual := policy.Value("password", "hunter2"); actual != "hunter2" {
+		t.Errorf("Expected keys without rules to be kept, got %v", actual)
+	}
+	if actual := NewPolicy().Value("token", Secret("abc")); actual != Redacted(Placeholder) {
+		t.Errorf("Expected Redactor values to be redacted without rules, got %v", actual)
+	}
+}
+
+// TestPolicyCacheBounded tests that long and dynamic keys do not grow the match cache without bound
+func TestPolicyCacheBounded(t *testing.T) {
+	policy := DefaultPolicy()
+
+	longKey := strings.Repeat("k", maxCachedKeyLength+1) + "_token"
+	if actual := policy.Value(longKey, "abc"); actual != Redacted(Placeholder) {
+		t.Errorf("Expected long keys to be redacted, got %v", actual)
+	}
+	if _, ok := policy.matches.Load(longKey); ok {
+		t.Error("Expected long keys not to be cached")
+	}
+
+	for index := 0; index < 2*maxCachedKeys; index++ {
+		policy.Value(fmt.Sprintf("key_%d", index), "value")
+	}
+	if cached := policy.cachedKeys.Load(); cached != maxCachedKeys {
+		t.Errorf("Expected %d cached keys, got %d", maxCachedKeys, cached)
+	}
+	if actual := policy.Value("late_password", "hunter2"); actual != Redacted(Placeholder) {
+		t.Errorf("Expected keys seen with a full cache to be redacted, got %v", actual)
+	}
+}
+
+// TestReplaceAttr tests redaction of slog attributes
+func TestReplaceAttr(t *testing.T) {
+	var buffer bytes.Buffer
+	handler := slog.NewJSONHandler(&buffer, &slog.HandlerOptions{ReplaceAttr: DefaultPolicy().ReplaceAttr})
+
+	slog.New(handler).Info("login",
+		slog.String("password", "hunter2"),
+		slog.Group("request", slog.String("authorization", "Bearer abc"), slog.Int("size", 10)),
+		slog.Any("key", Secret("abc")),
+	)
+
+	output := buffer.String()
+	for _, secret := range []string{"hunter2", "Bearer abc", `"key":"abc"`} {
+		if strings.Contains(output, secret) {
+			t.Errorf("Expected %q to be redacted, got %s", secret, output)
+		}
+	}
+	if !strings.Contains(output, `"size":10`) {
+		t.Errorf("Expected other attributes to be kept, got %s", output)
+	}
+}
@@ -0,0 +1,53 @@
+package reporter
+
+import (
+	"context"
+	"sync"
+)
+
+var (
+	defaultReporter Reporter = noopReporter{}
+	defaultMu       sync.RWMutex
+)
+
+// SetDefault sets the reporter used by the package level functions. Nil disables reporting.
+func SetDefault(r Reporter) {
+	if r == nil {
+		r = noopReporter{}
+	}
+
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+
+	defaultReporter = r
+}
+
+// Default returns the reporter used by the package level functions
+func Default() Reporter {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+
+	return defaultReporter
+}
+
+// Report reports the error with the default reporter
+func Report(ctx context.Context, err error) {
+	Default().Report(ctx, err)
+}
+
+// Flush flushes the default reporter
+func Flush(ctx context.Context) error {
+	return Default().Flush(ctx)
+}
+
+type noopReporter struct{}
+
+func (noopReporter) Report(context.Context, error) {}
+
+func (noopReporter) Flush(context.Context) error {
+	return nil
+}
+
+func (noopReporter) Close(context.Context) error {
+	return nil
+}
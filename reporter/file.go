@@ -0,0 +1,56 @@
+package reporter
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+)
+
+// FileTransport writes events as JSON lines
+type FileTransport struct {
+	mu     sync.Mutex
+	writer io.Writer
+	closer io.Closer
+}
+
+// NewFileTransport creates a transport appending events to the file at path
+func NewFileTransport(path string) (*FileTransport, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileTransport{
+		writer: file,
+		closer: file,
+	}, nil
+}
+
+// NewWriterTransport creates a transport writing events to w
+func NewWriterTransport(w io.Writer) *FileTransport {
+	return &FileTransport{writer: w}
+}
+
+func (t *FileTransport) Send(_ context.Context, event *Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	_, err = t.writer.Write(append(data, '\n'))
+	return err
+}
+
+// Close closes the underlying file
+func (t *FileTransport) Close() error {
+	if t.closer == nil {
+		return nil
+	}
+
+	return t.closer.Close()
+}
@@ -0,0 +1,72 @@
+package reporter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// Fingerprint groups errors by code and the functions of the top stack frames.
+// Line numbers are left out so that unrelated edits do not split groups.
+// Errors without a stack trace are grouped by code and message.
+func Fingerprint(err error, frames int) string {
+	hash := sha256.New()
+	hash.Write([]byte(syserr.GetCodeFromGenericError(err)))
+
+	stack := extractStack(err)
+	if len(stack) == 0 {
+		hash.Write([]byte("\n" + err.Error()))
+	}
+
+	for index, stackItem := range stack {
+		if index == frames {
+			break
+		}
+		hash.Write([]byte("\n" + stackItem.Function))
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))[:32]
+}
+
+// extractStack returns the stack of the innermost syserr error, which is closest to the failure
+func extractStack(err error) []*syserr.ErrorStackItem {
+	var stack []*syserr.ErrorStackItem
+
+	for err != nil {
+		var sErr *syserr.Error
+		if !errors.As(err, &sErr) {
+			break
+		}
+
+		if trace := sErr.StackTrace(); len(trace) > 0 {
+			stack = trace
+		}
+		err = sErr.Unwrap()
+	}
+
+	if stack == nil {
+		return nil
+	}
+
+	// Stacks captured while recovering start with the recovery handler, the deferred calls and the
+	// runtime panic machinery. Drop them so that panics are grouped by the function that panicked.
+	for index := len(stack) - 1; index >= 0; index-- {
+		if stack[index].Function == "runtime.gopanic" {
+			stack = stack[index+1:]
+			break
+		}
+	}
+	for len(stack) > 0 && strings.HasPrefix(stack[0].Function, "runtime.") {
+		stack = stack[1:]
+	}
+
+	// Drop runtime frames below the application code
+	for len(stack) > 0 && strings.HasPrefix(stack[len(stack)-1].Function, "runtime.") {
+		stack = stack[:len(stack)-1]
+	}
+
+	return stack
+}
@@ -0,0 +1,79 @@
+package reporter
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// NewLogHandler returns a slog handler reporting every record it handles, to be used as a logger sink
+// such as logger.Sink{Level: slog.LevelError, Handler: reporter.NewLogHandler(reporter.Default())}.
+// The error of an "err" or "error" attribute is reported when present, other records are reported
+// with their message and "code" attribute.
+func NewLogHandler(r Reporter) slog.Handler {
+	return &logHandler{reporter: r}
+}
+
+type logHandler struct {
+	reporter Reporter
+	// attrs are the attributes added with WithAttrs, groups are ignored
+	attrs []slog.Attr
+}
+
+// recordError is reported for records that do not carry an error, it has no stack
+// so that records are grouped by code and message
+type recordError struct {
+	code    syserr.Code
+	message string
+}
+
+func (e *recordError) Error() string {
+	return e.message
+}
+
+func (e *recordError) Code() syserr.Code {
+	return e.code
+}
+
+func (h *logHandler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+func (h *logHandler) Handle(ctx context.Context, record slog.Record) error {
+	err := &recordError{code: syserr.InternalCode, message: record.Message}
+	var attrErr error
+
+	inspect := func(attr slog.Attr) bool {
+		switch attr.Key {
+		case "err", "error":
+			if value, ok := attr.Value.Any().(error); ok {
+				attrErr = value
+			}
+		case "code":
+			err.code = syserr.Code(attr.Value.String())
+		}
+		return true
+	}
+
+	for _, attr := range h.attrs {
+		inspect(attr)
+	}
+	record.Attrs(inspect)
+
+	if attrErr != nil {
+		h.reporter.Report(ctx, attrErr)
+		return nil
+	}
+
+	h.reporter.Report(ctx, err)
+	return nil
+}
+
+func (h *logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &logHandler{reporter: h.reporter, attrs: append(h.attrs[:len(h.attrs):len(h.attrs)], attrs...)}
+}
+
+func (h *logHandler) WithGroup(string) slog.Handler {
+	return h
+}
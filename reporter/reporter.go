@@ -0,0 +1,313 @@
+package reporter
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	pkgContext "github.com/duongptryu/gox/context"
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/shutdown"
+	"github.com/duongptryu/gox/syserr"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultDedupWindow       = time.Minute
+	defaultFingerprintFrames = 3
+	defaultBufferSize        = 100
+)
+
+// Reporter sends unexpected errors to an error tracking system
+type Reporter interface {
+	// Report queues the error for sending. It never blocks the caller.
+	Report(ctx context.Context, err error)
+	// Flush waits until queued errors are sent or the context is done
+	Flush(ctx context.Context) error
+	// Close sends the queued errors and stops the reporter, errors reported afterwards are dropped.
+	// It returns when the queue is drained or the context is done.
+	Close(ctx context.Context) error
+}
+
+// Transport delivers events to their destination
+type Transport interface {
+	Send(ctx context.Context, event *Event) error
+}
+
+// Event is a reported error
+type Event struct {
+	ID          string                   `json:"id"`
+	Timestamp   time.Time                `json:"timestamp"`
+	Fingerprint string                   `json:"fingerprint"`
+	Code        syserr.Code              `json:"code"`
+	Message     string                   `json:"message"`
+	Stack       []*syserr.ErrorStackItem `json:"stack,omitempty"`
+	Fields      map[string]any           `json:"fields,omitempty"`
+	Tags        map[string]string        `json:"tags,omitempty"`
+	// Count is the number of occurrences the event stands for, including the ones
+	// suppressed by deduplication since the previous report of the same fingerprint
+	Count       int    `json:"count"`
+	Environment string `json:"environment,omitempty"`
+	Release     string `json:"release,omitempty"`
+	ServerName  string `json:"server_name,omitempty"`
+}
+
+// Config holds reporter configuration options
+type Config struct {
+	Transport Transport
+	// DedupWindow is the period during which errors with the same fingerprint are only counted.
+	// Zero uses one minute, a negative value disables deduplication.
+	DedupWindow time.Duration
+	// FingerprintFrames is the number of top stack frames used for fingerprinting. Zero uses 3 frames.
+	FingerprintFrames int
+	// BufferSize is the number of events queued for sending, extra events are dropped. Zero uses 100.
+	BufferSize  int
+	Environment string
+	Release     string
+	ServerName  string
+}
+
+// queueItem is either an event to send or a flush marker
+type queueItem struct {
+	event *Event
+	done  chan struct{}
+}
+
+// dedupEntry tracks occurrences of a fingerprint within the current window
+type dedupEntry struct {
+	windowStart time.Time
+	suppressed  int
+}
+
+type reporter struct {
+	config  Config
+	queue   chan queueItem
+	stopped chan struct{}
+	now     func() time.Time
+
+	// closing is set when the queue is closed, so that Report and Flush do not send on it
+	queueMu   sync.RWMutex
+	closing   bool
+	closeOnce sync.Once
+
+	mu        sync.Mutex
+	seen      map[string]*dedupEntry
+	lastPrune time.Time
+}
+
+// New creates a reporter sending events asynchronously through the configured transport.
+// The reporter is closed by the shutdown hooks.
+func New(config Config) (Reporter, error) {
+	if config.Transport == nil {
+		return nil, syserr.New(syserr.InvalidArgumentCode, "reporter transport is required")
+	}
+	if config.DedupWindow == 0 {
+		config.DedupWindow = defaultDedupWindow
+	}
+	if config.FingerprintFrames <= 0 {
+		config.FingerprintFrames = defaultFingerprintFrames
+	}
+	if config.BufferSize <= 0 {
+		config.BufferSize = defaultBufferSize
+	}
+
+	r := &reporter{
+		config:  config,
+		queue:   make(chan queueItem, config.BufferSize),
+		stopped: make(chan struct{}),
+		now:     time.Now,
+		seen:    map[string]*dedupEntry{},
+	}
+
+	go r.run()
+
+	shutdown.Register("reporter", r.Close)
+
+	return r, nil
+}
+
+func (r *reporter) Report(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+
+	fingerprint := Fingerprint(err, r.config.FingerprintFrames)
+
+	count, ok := r.dedup(fingerprint)
+	if !ok {
+		return
+	}
+
+	event := r.newEvent(ctx, err, fingerprint, count)
+
+	r.queueMu.RLock()
+	defer r.queueMu.RUnlock()
+
+	if r.closing {
+		return
+	}
+
+	select {
+	case r.queue <- queueItem{event: event}:
+	default:
+		logger.GetLogger().Warn("Error reporter buffer full, dropping event",
+			"fingerprint", fingerprint, "code", event.Code)
+	}
+}
+
+func (r *reporter) Flush(ctx context.Context) error {
+	done, err := r.enqueueFlush(ctx)
+	if err != nil || done == nil {
+		return err
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// enqueueFlush queues a flush marker, it returns a nil channel when the reporter is closed
+func (r *reporter) enqueueFlush(ctx context.Context) (chan struct{}, error) {
+	r.queueMu.RLock()
+	defer r.queueMu.RUnlock()
+
+	if r.closing {
+		return nil, nil
+	}
+
+	done := make(chan struct{})
+
+	select {
+	case r.queue <- queueItem{done: done}:
+		return done, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (r *reporter) Close(ctx context.Context) error {
+	r.closeOnce.Do(func() {
+		r.queueMu.Lock()
+		r.closing = true
+		close(r.queue)
+		r.queueMu.Unlock()
+	})
+
+	select {
+	case <-r.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// dedup reports whether the fingerprint should be sent and the number of occurrences it stands for
+func (r *reporter) dedup(fingerprint string) (int, bool) {
+	if r.config.DedupWindow < 0 {
+		return 1, true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	r.prune(now)
+
+	entry, ok := r.seen[fingerprint]
+	if ok && now.Sub(entry.windowStart) < r.config.DedupWindow {
+		entry.suppressed++
+		return 0, false
+	}
+
+	count := 1
+	if ok {
+		count += entry.suppressed
+	}
+
+	r.seen[fingerprint] = &dedupEntry{windowStart: now}
+
+	return count, true
+}
+
+// prune removes expired fingerprints without suppressed occurrences, once per window
+func (r *reporter) prune(now time.Time) {
+	if now.Sub(r.lastPrune) < r.config.DedupWindow {
+		return
+	}
+
+	for fingerprint, entry := range r.seen {
+		if entry.suppressed == 0 && now.Sub(entry.windowStart) >= r.config.DedupWindow {
+			delete(r.seen, fingerprint)
+		}
+	}
+
+	r.lastPrune = now
+}
+
+func (r *reporter) newEvent(ctx context.Context, err error, fingerprint string, count int) *Event {
+	event := &Event{
+		ID:          uuid.NewString(),
+		Timestamp:   r.now().UTC(),
+		Fingerprint: fingerprint,
+		Code:        syserr.GetCodeFromGenericError(err),
+		Message:     err.Error(),
+		Stack:       extractStack(err),
+		Count:       count,
+		Environment: r.config.Environment,
+		Release:     r.config.Release,
+		ServerName:  r.config.ServerName,
+	}
+
+	if fields := syserr.GetFieldsFromGenericError(err); len(fields) > 0 {
+		event.Fields = make(map[string]any, len(fields))
+		for _, field := range fields {
+			event.Fields[field.Key] = field.Value
+		}
+	}
+
+	if ctx != nil {
+		event.Tags = extractContextTags(ctx)
+	}
+
+	return event
+}
+
+func (r *reporter) run() {
+	defer close(r.stopped)
+
+	for item := range r.queue {
+		if item.done != nil {
+			close(item.done)
+			continue
+		}
+
+		if err := r.config.Transport.Send(context.Background(), item.event); err != nil {
+			logger.GetLogger().Warn("Failed to send error report",
+				"error", err.Error(), "fingerprint", item.event.Fingerprint)
+		}
+	}
+}
+
+func extractContextTags(ctx context.Context) map[string]string {
+	tags := map[string]string{}
+
+	if operationID := pkgContext.GetOperationID(ctx); operationID != "" {
+		tags["operation_id"] = operationID
+	}
+	if requestID := pkgContext.GetRequestID(ctx); requestID != "" {
+		tags["request_id"] = requestID
+	}
+	if userID := pkgContext.GetUserIDFromContext(ctx); userID != "" {
+		tags["user_id"] = userID
+	}
+
+	if len(tags) == 0 {
+		return nil
+	}
+
+	return tags
+}
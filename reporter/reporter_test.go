@@ -0,0 +1,217 @@
+package reporter
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	pkgContext "github.com/duongptryu/gox/context"
+	"github.com/duongptryu/gox/syserr"
+)
+
+type memoryTransport struct {
+	mu     sync.Mutex
+	events []*Event
+}
+
+func (t *memoryTransport) Send(_ context.Context, event *Event) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.events = append(t.events, event)
+	return nil
+}
+
+func (t *memoryTransport) Events() []*Event {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]*Event(nil), t.events...)
+}
+
+func newTestReporter(t *testing.T, config Config) *reporter {
+	t.Helper()
+
+	r, err := New(config)
+	if err != nil {
+		t.Fatalf("Failed to create reporter: %v", err)
+	}
+
+	return r.(*reporter)
+}
+
+func newInternalError(message string) error {
+	return syserr.New(syserr.InternalCode, message, syserr.F("order_id", 42))
+}
+
+// TestFingerprint tests that errors are grouped by code and top frames
+func TestFingerprint(t *testing.T) {
+	first := Fingerprint(newInternalError("first"), 3)
+	second := Fingerprint(newInternalError("second"), 3)
+	if first != second {
+		t.Errorf("Expected errors created at the same place to share a fingerprint")
+	}
+
+	if other := Fingerprint(syserr.New(syserr.InternalCode, "other"), 3); other == first {
+		t.Errorf("Expected errors created at different places to have different fingerprints")
+	}
+
+	if Fingerprint(errors.New("a"), 3) == Fingerprint(errors.New("b"), 3) {
+		t.Errorf("Expected errors without stack to be grouped by message")
+	}
+}
+
+// TestReporterDeduplication tests that repeated errors are counted within the window
+func TestReporterDeduplication(t *testing.T) {
+	transport := &memoryTransport{}
+	r := newTestReporter(t, Config{Transport: transport, DedupWindow: time.Minute})
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	r.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		r.Report(ctx, newInternalError("boom"))
+	}
+
+	now = now.Add(time.Minute)
+	r.Report(ctx, newInternalError("boom"))
+
+	if err := r.Flush(ctx); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	events := transport.Events()
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(events))
+	}
+	if events[0].Count != 1 || events[1].Count != 3 {
+		t.Errorf("Expected counts 1 and 3, got %d and %d", events[0].Count, events[1].Count)
+	}
+}
+
+// TestReporterEvent tests the content of a reported event
+func TestReporterEvent(t *testing.T) {
+	transport := &memoryTransport{}
+	r := newTestReporter(t, Config{Transport: transport, Environment: "test", Release: "1.0.0"})
+
+	ctx := pkgContext.WithRequestID(context.Background(), "req-1")
+	r.Report(ctx, syserr.Wrap(newInternalError("boom"), syserr.InternalCode, "failed to create order"))
+
+	if err := r.Flush(ctx); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	events := transport.Events()
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(events))
+	}
+
+	event := events[0]
+	if event.Code != syserr.InternalCode || event.Message != "failed to create order: boom" {
+		t.Errorf("Unexpected code or message: %s %q", event.Code, event.Message)
+	}
+	if event.Fields["order_id"] != 42 {
+		t.Errorf("Expected order_id field, got %v", event.Fields)
+	}
+	if event.Tags["request_id"] != "req-1" {
+		t.Errorf("Expected request_id tag, got %v", event.Tags)
+	}
+	if len(event.Stack) == 0 || event.Stack[0].Function == "" {
+		t.Errorf("Expected stack frames, got %v", event.Stack)
+	}
+	if event.Environment != "test" || event.Release != "1.0.0" {
+		t.Errorf("Expected environment and release, got %q %q", event.Environment, event.Release)
+	}
+}
+
+// TestDefaultReporter tests the package level functions
+func TestDefaultReporter(t *testing.T) {
+	ctx := context.Background()
+
+	// The default reporter is a no-op
+	Report(ctx, newInternalError("boom"))
+	if err := Flush(ctx); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	transport := &memoryTransport{}
+	SetDefault(newTestReporter(t, Config{Transport: transport}))
+	defer SetDefault(nil)
+
+	Report(ctx, newInternalError("boom"))
+	if err := Flush(ctx); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	if len(transport.Events()) != 1 {
+		t.Errorf("Expected 1 event, got %d", len(transport.Events()))
+	}
+}
+
+// TestLogHandler tests that log records are reported with their error or code
+func TestLogHandler(t *testing.T) {
+	transport := &memoryTransport{}
+	r := newTestReporter(t, Config{Transport: transport, DedupWindow: -1})
+	log := slog.New(NewLogHandler(r)).With("component", "orders")
+	ctx := context.Background()
+
+	log.ErrorContext(ctx, "Payment failed", "err", newInternalError("gateway timeout"))
+	log.ErrorContext(ctx, "Order not found", "code", syserr.NotFoundCode)
+
+	if err := r.Flush(ctx); err != nil {
+		t.Fatalf("Unexpected flush error: %v", err)
+	}
+
+	events := transport.Events()
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(events))
+	}
+
+	if events[0].Message != "gateway timeout" || events[0].Fields["order_id"] != 42 || len(events[0].Stack) == 0 {
+		t.Errorf("Expected the error attribute to be reported, got %+v", events[0])
+	}
+	if events[1].Message != "Order not found" || events[1].Code != syserr.NotFoundCode || len(events[1].Stack) != 0 {
+		t.Errorf("Expected the record to be reported with its code, got %+v", events[1])
+	}
+}
+
+// TestNewWithoutTransport tests that a reporter requires a transport
+func TestNewWithoutTransport(t *testing.T) {
+	if _, err := New(Config{}); syserr.GetCodeFromGenericError(err) != syserr.InvalidArgumentCode {
+		t.Errorf("Expected an invalid argument error, got %v", err)
+	}
+}
+
+// TestReporterClose tests that closing sends the queued errors and drops the later ones
+func TestReporterClose(t *testing.T) {
+	transport := &memoryTransport{}
+	r := newTestReporter(t, Config{Transport: transport, DedupWindow: -1})
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		r.Report(ctx, newInternalError("boom"))
+	}
+
+	if err := r.Close(ctx); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if len(transport.Events()) != 3 {
+		t.Errorf("Expected the 3 queued events to be sent, got %d", len(transport.Events()))
+	}
+
+	// Using a closed reporter does not panic
+	r.Report(ctx, newInternalError("boom"))
+	if err := r.Flush(ctx); err != nil {
+		t.Errorf("Expected no flush error, got %v", err)
+	}
+	if err := r.Close(ctx); err != nil {
+		t.Errorf("Expected no error when closing twice, got %v", err)
+	}
+	if len(transport.Events()) != 3 {
+		t.Errorf("Expected errors reported after Close to be dropped, got %d events", len(transport.Events()))
+	}
+}
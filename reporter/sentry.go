@@ -0,0 +1,206 @@
+package reporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+const (
+	sentryEnvelopeContentType = "application/x-sentry-envelope"
+	sentryClient              = "gox-reporter/1.0"
+	defaultSentryTimeout      = 10 * time.Second
+)
+
+// SentryTransport sends events to a Sentry-compatible envelope endpoint
+type SentryTransport struct {
+	client    *http.Client
+	dsn       string
+	endpoint  string
+	publicKey string
+}
+
+// NewSentryTransport creates a transport for a DSN of the form scheme://public_key@host[/path]/project_id.
+// A nil client uses an http.Client with a 10 second timeout.
+func NewSentryTransport(dsn string, client *http.Client) (*SentryTransport, error) {
+	parsed, err := url.Parse(dsn)
+	if err != nil {
+		return nil, syserr.Wrap(err, syserr.InvalidArgumentCode, "invalid sentry dsn")
+	}
+
+	publicKey := parsed.User.Username()
+	lastSlash := strings.LastIndex(parsed.Path, "/")
+	if publicKey == "" || lastSlash < 0 || parsed.Path[lastSlash+1:] == "" {
+		return nil, syserr.New(syserr.InvalidArgumentCode, "invalid sentry dsn, public key and project id are required")
+	}
+
+	projectID := parsed.Path[lastSlash+1:]
+	endpoint := fmt.Sprintf("%s://%s%s/api/%s/envelope/", parsed.Scheme, parsed.Host, parsed.Path[:lastSlash], projectID)
+
+	if client == nil {
+		client = &http.Client{Timeout: defaultSentryTimeout}
+	}
+
+	return &SentryTransport{
+		client:    client,
+		dsn:       dsn,
+		endpoint:  endpoint,
+		publicKey: publicKey,
+	}, nil
+}
+
+func (t *SentryTransport) Send(ctx context.Context, event *Event) error {
+	body, err := t.buildEnvelope(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", sentryEnvelopeContentType)
+	req.Header.Set("X-Sentry-Auth", fmt.Sprintf("Sentry sentry_version=7, sentry_client=%s, sentry_key=%s", sentryClient, t.publicKey))
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Drain the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("sentry responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+type sentryFrame struct {
+	Function string `json:"function,omitempty"`
+	AbsPath  string `json:"abs_path,omitempty"`
+	Lineno   int    `json:"lineno,omitempty"`
+	InApp    bool   `json:"in_app"`
+}
+
+type sentryStacktrace struct {
+	Frames []sentryFrame `json:"frames"`
+}
+
+type sentryException struct {
+	Type       string            `json:"type"`
+	Value      string            `json:"value"`
+	Stacktrace *sentryStacktrace `json:"stacktrace,omitempty"`
+}
+
+type sentryExceptions struct {
+	Values []sentryException `json:"values"`
+}
+
+type sentryEvent struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   string            `json:"timestamp"`
+	Platform    string            `json:"platform"`
+	Level       string            `json:"level"`
+	ServerName  string            `json:"server_name,omitempty"`
+	Release     string            `json:"release,omitempty"`
+	Environment string            `json:"environment,omitempty"`
+	Fingerprint []string          `json:"fingerprint"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Extra       map[string]any    `json:"extra,omitempty"`
+	Exception   sentryExceptions  `json:"exception"`
+}
+
+func (t *SentryTransport) buildEnvelope(event *Event) ([]byte, error) {
+	eventID := strings.ReplaceAll(event.ID, "-", "")
+
+	payload, err := json.Marshal(newSentryEvent(eventID, event))
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := json.Marshal(map[string]string{
+		"event_id": eventID,
+		"sent_at":  time.Now().UTC().Format(time.RFC3339Nano),
+		"dsn":      t.dsn,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	itemHeader, err := json.Marshal(map[string]any{
+		"type":   "event",
+		"length": len(payload),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+	buffer.Write(header)
+	buffer.WriteByte('\n')
+	buffer.Write(itemHeader)
+	buffer.WriteByte('\n')
+	buffer.Write(payload)
+	buffer.WriteByte('\n')
+
+	return buffer.Bytes(), nil
+}
+
+func newSentryEvent(eventID string, event *Event) *sentryEvent {
+	tags := map[string]string{"code": string(event.Code)}
+	for key, value := range event.Tags {
+		tags[key] = value
+	}
+
+	extra := map[string]any{"count": event.Count}
+	for key, value := range event.Fields {
+		extra[key] = value
+	}
+
+	exception := sentryException{
+		Type:  string(event.Code),
+		Value: event.Message,
+	}
+
+	if len(event.Stack) > 0 {
+		// Sentry expects the oldest frame first
+		frames := make([]sentryFrame, len(event.Stack))
+		for index, stackItem := range event.Stack {
+			line, _ := strconv.Atoi(stackItem.Line)
+			frames[len(event.Stack)-1-index] = sentryFrame{
+				Function: stackItem.Function,
+				AbsPath:  stackItem.File,
+				Lineno:   line,
+				InApp:    true,
+			}
+		}
+		exception.Stacktrace = &sentryStacktrace{Frames: frames}
+	}
+
+	return &sentryEvent{
+		EventID:     eventID,
+		Timestamp:   event.Timestamp.Format(time.RFC3339Nano),
+		Platform:    "go",
+		Level:       "error",
+		ServerName:  event.ServerName,
+		Release:     event.Release,
+		Environment: event.Environment,
+		Fingerprint: []string{event.Fingerprint},
+		Tags:        tags,
+		Extra:       extra,
+		Exception:   sentryExceptions{Values: []sentryException{exception}},
+	}
+}
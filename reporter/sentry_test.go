@@ -0,0 +1,140 @@
+package reporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestSentryTransport tests the envelope sent to a Sentry-compatible endpoint
+func TestSentryTransport(t *testing.T) {
+	var (
+		path string
+		auth string
+		body []byte
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		auth = r.Header.Get("X-Sentry-Auth")
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	dsn := strings.Replace(server.URL, "://", "://public-key@", 1) + "/42"
+	transport, err := NewSentryTransport(dsn, server.Client())
+	if err != nil {
+		t.Fatalf("NewSentryTransport failed: %v", err)
+	}
+
+	r := newTestReporter(t, Config{Transport: transport})
+	r.Report(context.Background(), newInternalError("boom"))
+	if err := r.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	if path != "/api/42/envelope/" {
+		t.Errorf("Expected envelope path, got %s", path)
+	}
+	if !strings.Contains(auth, "sentry_key=public-key") {
+		t.Errorf("Expected sentry key in auth header, got %s", auth)
+	}
+
+	lines := bytes.Split(bytes.TrimSpace(body), []byte("\n"))
+	if len(lines) != 3 {
+		t.Fatalf("Expected envelope header, item header and payload, got %d lines", len(lines))
+	}
+
+	var itemHeader struct {
+		Type   string `json:"type"`
+		Length int    `json:"length"`
+	}
+	if err := json.Unmarshal(lines[1], &itemHeader); err != nil {
+		t.Fatalf("Failed to decode item header: %v", err)
+	}
+	if itemHeader.Type != "event" || itemHeader.Length != len(lines[2]) {
+		t.Errorf("Unexpected item header %+v", itemHeader)
+	}
+
+	var event sentryEvent
+	if err := json.Unmarshal(lines[2], &event); err != nil {
+		t.Fatalf("Failed to decode event: %v", err)
+	}
+	if len(event.EventID) != 32 || event.Tags["code"] != "internal" || len(event.Fingerprint) != 1 {
+		t.Errorf("Unexpected event %+v", event)
+	}
+
+	exception := event.Exception.Values[0]
+	frames := exception.Stacktrace.Frames
+	if exception.Value != "boom" || !strings.HasSuffix(frames[len(frames)-1].Function, "newInternalError") {
+		t.Errorf("Expected the failing frame last, got %+v", exception)
+	}
+}
+
+// TestSentryTransportErrorStatus tests that non-2xx responses are returned as errors
+func TestSentryTransportErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	transport, err := NewSentryTransport(strings.Replace(server.URL, "://", "://key@", 1)+"/1", nil)
+	if err != nil {
+		t.Fatalf("NewSentryTransport failed: %v", err)
+	}
+
+	if err := transport.Send(context.Background(), &Event{ID: "id"}); err == nil {
+		t.Error("Expected an error for status 429")
+	}
+}
+
+// TestNewSentryTransportInvalidDSN tests DSN validation
+func TestNewSentryTransportInvalidDSN(t *testing.T) {
+	for _, dsn := range []string{"https://sentry.io/42", "https://key@sentry.io/", "://bad"} {
+		if _, err := NewSentryTransport(dsn, nil); err == nil {
+			t.Errorf("Expected an error for dsn %q", dsn)
+		}
+	}
+}
+
+// TestFileTransport tests that events are appended as JSON lines
+func TestFileTransport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "errors.log")
+
+	transport, err := NewFileTransport(path)
+	if err != nil {
+		t.Fatalf("NewFileTransport failed: %v", err)
+	}
+
+	for _, id := range []string{"first", "second"} {
+		if err := transport.Send(context.Background(), &Event{ID: id, Code: "internal"}); err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+	}
+	if err := transport.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d", len(lines))
+	}
+
+	var event Event
+	if err := json.Unmarshal([]byte(lines[1]), &event); err != nil || event.ID != "second" {
+		t.Errorf("Unexpected line %s: %v", lines[1], err)
+	}
+}
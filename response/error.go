@@ -0,0 +1,44 @@
+package response
+
+import (
+	"encoding/json"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+// remoteErrorRes holds the members of both the error envelope and problem details documents
+type remoteErrorRes struct {
+	IsError    bool                `json:"is_error"`
+	Code       string              `json:"code"`
+	Message    string              `json:"message"`
+	Details    json.RawMessage     `json:"details"`
+	Detail     string              `json:"detail"`
+	Violations []*syserr.Violation `json:"violations"`
+}
+
+// ParseErrorResponse converts an error envelope or problem details document returned by a
+// downstream service into a syserr error. It returns nil if the body is not an error response.
+func ParseErrorResponse(body []byte) *syserr.Error {
+	var res remoteErrorRes
+	if err := json.Unmarshal(body, &res); err != nil || res.Code == "" {
+		return nil
+	}
+
+	message := res.Message
+	if !res.IsError {
+		message = res.Detail
+	}
+
+	violations := res.Violations
+	if len(violations) == 0 && len(res.Details) > 0 {
+		// details may hold anything, only violations are recovered
+		_ = json.Unmarshal(res.Details, &violations)
+	}
+
+	if len(violations) > 0 {
+		return syserr.Wrap(syserr.NewValidationError(violations...), syserr.Code(res.Code), message).
+			WithPublicMessage(message)
+	}
+
+	return syserr.NewPublic(syserr.Code(res.Code), message)
+}
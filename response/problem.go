@@ -0,0 +1,78 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProblemContentType is the media type of RFC 7807 problem details documents
+const ProblemContentType = "application/problem+json"
+
+// problemRes represents an RFC 7807 problem details document
+type problemRes struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Extensions map[string]interface{}
+}
+
+// NewProblemResponse creates a new problem details response with the "about:blank" type
+func NewProblemResponse(status int, detail string) *problemRes {
+	return &problemRes{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
+
+// WithType sets the URI reference that identifies the problem type
+func (r *problemRes) WithType(problemType string) *problemRes {
+	r.Type = problemType
+	return r
+}
+
+// WithInstance sets the URI reference that identifies the occurrence of the problem
+func (r *problemRes) WithInstance(instance string) *problemRes {
+	r.Instance = instance
+	return r
+}
+
+// WithExtension adds an extension member to the problem details document
+func (r *problemRes) WithExtension(key string, value interface{}) *problemRes {
+	if r.Extensions == nil {
+		r.Extensions = make(map[string]interface{})
+	}
+	r.Extensions[key] = value
+	return r
+}
+
+// MarshalJSON renders the standard members with the extension members at the top level
+func (r *problemRes) MarshalJSON() ([]byte, error) {
+	document := make(map[string]interface{}, len(r.Extensions)+5)
+	for key, value := range r.Extensions {
+		document[key] = value
+	}
+
+	document["type"] = r.Type
+	document["title"] = r.Title
+	document["status"] = r.Status
+	if r.Detail != "" {
+		document["detail"] = r.Detail
+	}
+	if r.Instance != "" {
+		document["instance"] = r.Instance
+	}
+
+	return json.Marshal(document)
+}
+
+// JSON sends the problem details document with the application/problem+json content type
+func (r *problemRes) JSON(c *gin.Context) {
+	c.Header("Content-Type", ProblemContentType)
+	c.JSON(r.Status, r)
+}
@@ -2,6 +2,7 @@ package httpserver
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -10,8 +11,12 @@ import (
 	"time"
 
 	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/shutdown"
 )
 
+// flushTimeout bounds the logger flush following the shutdown hooks
+const flushTimeout = 2 * time.Second
+
 // Config holds the server configuration
 type Config struct {
 	Host         string
@@ -81,14 +86,32 @@ func (s *Server) shutdown(ctx context.Context) error {
 
 	logger.Info(ctx, "Shutting down HTTP server...")
 
+	var errs []error
+
 	// Attempt graceful shutdown
 	if err := s.httpServer.Shutdown(shutdownCtx); err != nil {
 		logger.Error(ctx, "Server forced to shutdown", logger.F("error", err))
-		return fmt.Errorf("server forced to shutdown: %w", err)
+		errs = append(errs, fmt.Errorf("server forced to shutdown: %w", err))
+	} else {
+		logger.Info(ctx, "HTTP server shut down gracefully")
+	}
+
+	// Release the resources registered by the application even when the server was forced to shut down.
+	// The hooks get a deadline of their own, the server may have used up shutdownCtx. The logger is flushed last.
+	hooksCtx, cancelHooks := context.WithTimeout(context.Background(), shutdown.DefaultTimeout)
+	defer cancelHooks()
+
+	if err := shutdown.Run(hooksCtx); err != nil {
+		logger.Error(ctx, "Shutdown hooks failed", logger.F("error", err))
+		errs = append(errs, fmt.Errorf("shutdown hooks failed: %w", err))
 	}
 
-	logger.Info(ctx, "HTTP server shut down gracefully")
-	return nil
+	// The hooks may have used up hooksCtx, or timed out before flushing the logger
+	flushCtx, cancelFlush := context.WithTimeout(context.Background(), flushTimeout)
+	defer cancelFlush()
+	_ = logger.Flush(flushCtx)
+
+	return errors.Join(errs...)
 }
 
 // Addr returns the server address
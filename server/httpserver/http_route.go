@@ -15,6 +15,12 @@ type RouterConfig struct {
 	Environment string
 	EnableCORS  bool
 	EnableAuth  bool
+	// ErrorFormat selects the default error rendering, envelope or RFC 7807 problem details
+	ErrorFormat middleware.ErrorFormat
+	// ProblemTypeBaseURI is prefixed to error codes to build problem type URIs
+	ProblemTypeBaseURI string
+	// RenderErrorFields adds the redacted error fields to problem details
+	RenderErrorFields bool
 }
 
 // SetupRouter creates and configures a Gin router with standard middleware
@@ -55,7 +61,11 @@ func setupCoreMiddleware(router *gin.Engine, config RouterConfig) {
 	}
 
 	// Error handling middleware (should be last)
-	router.Use(middleware.ErrorHandler())
+	router.Use(middleware.ErrorHandlerWithConfig(middleware.ErrorHandlerConfig{
+		Format:             config.ErrorFormat,
+		ProblemTypeBaseURI: config.ProblemTypeBaseURI,
+		RenderFields:       config.RenderErrorFields,
+	}))
 }
 
 // setupHealthEndpoints adds standard health check endpoints
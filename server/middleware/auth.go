@@ -15,7 +15,7 @@ func RequireAuth(jwtService *auth.JWTService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token := extractTokenFromHeader(c.GetHeader("Authorization"))
 		if token == "" {
-			c.Error(syserr.New(syserr.UnauthorizedCode, "authorization token required"))
+			c.Error(syserr.NewPublic(syserr.UnauthorizedCode, "authorization token required"))
 			return
 		}
 
@@ -1,46 +1,153 @@
 package middleware
 
 import (
-	"errors"
-	"net/http"
+	"math"
+	"strconv"
+	"strings"
 
+	pkgContext "github.com/duongptryu/gox/context"
 	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/reporter"
 	"github.com/duongptryu/gox/response"
 	"github.com/duongptryu/gox/syserr"
 
 	"github.com/gin-gonic/gin"
 )
 
+// ErrorFormat selects how errors are rendered to clients
+type ErrorFormat int
+
+const (
+	// ErrorFormatEnvelope renders errors with the is_error/code/message envelope
+	ErrorFormatEnvelope ErrorFormat = iota
+	// ErrorFormatProblem renders errors as RFC 7807 application/problem+json documents
+	ErrorFormatProblem
+)
+
+const errorHandlerConfigKey = "gox.errorHandlerConfig"
+
+// ErrorHandlerConfig holds error handler configuration options
+type ErrorHandlerConfig struct {
+	// Format is the default error format. Clients accepting application/problem+json always get problem details.
+	Format ErrorFormat
+	// ProblemTypeBaseURI is prefixed to the error code to build the problem type URI.
+	// "about:blank" is used when empty.
+	ProblemTypeBaseURI string
+	// RenderFields adds the error fields to problem details under the "fields" member, redacted with
+	// the redact policy. Fields may hold internal details such as SQL states, so they are not rendered by default.
+	RenderFields bool
+	// JSONFieldNames names validation violations by the json tag of the field, e.g. "email" rather than "Email".
+	// It registers a tag name function on the global gin validator, so FieldError.Field() also returns
+	// json names in application code.
+	JSONFieldNames bool
+}
+
 func ErrorHandler() gin.HandlerFunc {
+	return ErrorHandlerWithConfig(ErrorHandlerConfig{})
+}
+
+// ErrorHandlerWithConfig returns an error handler rendering errors with the given configuration
+func ErrorHandlerWithConfig(config ErrorHandlerConfig) gin.HandlerFunc {
+	if config.JSONFieldNames {
+		registerJSONTagNames()
+	}
+
 	return func(c *gin.Context) {
+		// Make the configuration available to Recovery
+		c.Set(errorHandlerConfigKey, config)
+
 		c.Next()
 
 		if len(c.Errors) > 0 {
-			err := c.Errors.Last().Err
-			handleError(c, err)
+			last := c.Errors.Last()
+			handleError(c, config, last.Err, last.Type)
 		}
 	}
 }
 
-func handleError(c *gin.Context, err error) {
-	var sysErr *syserr.Error
-	if errors.As(err, &sysErr) {
-		// statusCode := getHTTPStatusCode(sysErr.Code())
-		c.JSON(http.StatusOK, response.NewErrorResponse(
-			string(sysErr.Code()),
-			sysErr.Error(),
-			nil,
-		))
+// handleError renders the error for the client. Only the public message is sent,
+// the internal message and wrapped errors are kept for logs.
+func handleError(c *gin.Context, config ErrorHandlerConfig, err error, errorType gin.ErrorType) {
+	err = convertBindingError(err, errorType)
+
+	// Every error is logged at the level of its code, expected errors such as not found are warnings
+	logger.LogError(c.Request.Context(), err)
+
+	if syserr.GetCodeFromGenericError(err) == syserr.InternalCode {
+		reporter.Report(c.Request.Context(), err)
+	}
+
+	renderError(c, config, err)
+}
+
+// renderError writes the code, public message, violations and retry-after hint in the configured format.
+// Error fields are only rendered in problem details when enabled by ErrorHandlerConfig.RenderFields.
+func renderError(c *gin.Context, config ErrorHandlerConfig, err error) {
+	code := syserr.GetCodeFromGenericError(err)
+	statusCode := getHTTPStatusCode(code)
+	message := syserr.GetPublicMessageFromGenericError(err)
+
+	if retryAfter := syserr.GetRetryAfterFromGenericError(err); retryAfter > 0 {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	}
+
+	// Violations are rendered as details, nil keeps the details slot empty
+	var details interface{}
+	violations := syserr.GetViolationsFromGenericError(err)
+	if len(violations) > 0 {
+		details = violations
+	}
+
+	if config.Format != ErrorFormatProblem && !acceptsProblem(c) {
+		response.NewErrorResponse(string(code), message, details).JSON(c, statusCode)
 		return
 	}
 
-	// log error
-	logger.LogError(c.Request.Context(), err)
+	problem := response.NewProblemResponse(statusCode, message).
+		WithInstance(c.Request.URL.Path).
+		WithExtension("code", string(code))
+
+	if config.ProblemTypeBaseURI != "" {
+		problem.WithType(config.ProblemTypeBaseURI + string(code))
+	}
+
+	if requestID := pkgContext.GetRequestID(c.Request.Context()); requestID != "" {
+		problem.WithExtension("request_id", requestID)
+	}
+
+	if config.RenderFields {
+		if fields := syserr.GetFieldsFromGenericError(err); len(fields) > 0 {
+			problem.WithExtension("fields", convertFieldsToMap(fields))
+		}
+	}
+
+	if details != nil {
+		problem.WithExtension("violations", details)
+	}
+
+	problem.JSON(c)
+}
+
+func acceptsProblem(c *gin.Context) bool {
+	return strings.Contains(c.GetHeader("Accept"), response.ProblemContentType)
+}
+
+// getErrorHandlerConfig returns the configuration set by ErrorHandlerWithConfig, or the default one
+func getErrorHandlerConfig(c *gin.Context) ErrorHandlerConfig {
+	if value, ok := c.Get(errorHandlerConfigKey); ok {
+		if config, ok := value.(ErrorHandlerConfig); ok {
+			return config
+		}
+	}
+	return ErrorHandlerConfig{}
+}
+
+func convertFieldsToMap(fields []*syserr.Field) map[string]interface{} {
+	result := make(map[string]interface{}, len(fields))
+
+	for _, field := range fields {
+		result[field.Key] = field.Value
+	}
 
-	// Default error
-	c.JSON(http.StatusOK, response.NewErrorResponse(
-		"internal_error",
-		"An error occurred",
-		nil,
-	))
+	return result
 }
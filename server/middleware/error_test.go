@@ -0,0 +1,481 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/duongptryu/gox/logger"
+	"github.com/duongptryu/gox/logger/loggertest"
+	"github.com/duongptryu/gox/reporter"
+	"github.com/duongptryu/gox/syserr"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newTestRouter(err error) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	logger.Init(&logger.Config{Level: slog.LevelInfo, Output: io.Discard})
+
+	router := gin.New()
+	router.Use(ErrorHandler())
+	router.GET("/", func(c *gin.Context) {
+		c.Error(err)
+	})
+
+	return router
+}
+
+// TestErrorHandlerStatusCodes tests that error codes are mapped to HTTP status codes
+func TestErrorHandlerStatusCodes(t *testing.T) {
+	testCases := []struct {
+		err      error
+		expected int
+	}{
+		{syserr.New(syserr.InternalCode, "internal"), http.StatusInternalServerError},
+		{syserr.New(syserr.InvalidArgumentCode, "invalid argument"), http.StatusBadRequest},
+		{syserr.New(syserr.NotFoundCode, "not found"), http.StatusNotFound},
+		{syserr.New(syserr.ConflictCode, "conflict"), http.StatusConflict},
+		{syserr.New(syserr.UnauthorizedCode, "unauthorized"), http.StatusUnauthorized},
+		{syserr.New(syserr.ForbiddenCode, "forbidden"), http.StatusForbidden},
+		{syserr.New(syserr.ValidationCode, "validation"), http.StatusBadRequest},
+		{syserr.New(syserr.Code("unknown_code"), "unknown"), http.StatusInternalServerError},
+		{errors.New("plain error"), http.StatusInternalServerError},
+	}
+
+	for _, testCase := range testCases {
+		recorder := httptest.NewRecorder()
+		newTestRouter(testCase.err).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if recorder.Code != testCase.expected {
+			t.Errorf("Expected status %d for %v, got %d", testCase.expected, testCase.err, recorder.Code)
+		}
+	}
+}
+
+// TestSetHTTPStatusCode tests overriding the status code of an error code
+func TestSetHTTPStatusCode(t *testing.T) {
+	code := syserr.Code("payment_required")
+	SetHTTPStatusCode(code, http.StatusPaymentRequired)
+
+	recorder := httptest.NewRecorder()
+	newTestRouter(syserr.New(code, "payment required")).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if recorder.Code != http.StatusPaymentRequired {
+		t.Errorf("Expected status %d, got %d", http.StatusPaymentRequired, recorder.Code)
+	}
+}
+
+// TestErrorHandlerPublicMessage tests that only the public message is sent to the client
+func TestErrorHandlerPublicMessage(t *testing.T) {
+	testCases := []struct {
+		err      error
+		expected string
+	}{
+		{
+			syserr.Wrap(errors.New("pq: relation \"users\" does not exist"), syserr.InternalCode, "failed to query users"),
+			syserr.GetCodeInfo(syserr.InternalCode).Message,
+		},
+		{
+			syserr.Wrap(errors.New("duplicate key value"), syserr.ConflictCode, "failed to insert user").
+				WithPublicMessage("email already registered"),
+			"email already registered",
+		},
+		{
+			syserr.Wrap(syserr.NewPublic(syserr.NotFoundCode, "user not found"), syserr.NotFoundCode, "failed to load profile"),
+			"user not found",
+		},
+		{
+			errors.New("dial tcp: connection refused"),
+			syserr.GetCodeInfo(syserr.InternalCode).Message,
+		},
+	}
+
+	for _, testCase := range testCases {
+		recorder := httptest.NewRecorder()
+		newTestRouter(testCase.err).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		var body struct {
+			Message string `json:"message"`
+		}
+		if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+
+		if body.Message != testCase.expected {
+			t.Errorf("Expected message '%s', got '%s'", testCase.expected, body.Message)
+		}
+	}
+}
+
+// TestErrorHandlerProblemDetails tests rendering errors as RFC 7807 problem details
+func TestErrorHandlerProblemDetails(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(RequestContext())
+	router.Use(ErrorHandlerWithConfig(ErrorHandlerConfig{
+		Format:             ErrorFormatProblem,
+		ProblemTypeBaseURI: "https://errors.example.com/",
+		RenderFields:       true,
+	}))
+	router.GET("/users/:id", func(c *gin.Context) {
+		c.Error(syserr.NewPublic(syserr.NotFoundCode, "user not found", syserr.F("user_id", "42"), syserr.F("api_token", "secret")))
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	request.Header.Set("X-Request-ID", "request-123")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	if contentType := recorder.Header().Get("Content-Type"); contentType != "application/problem+json" {
+		t.Errorf("Expected content type 'application/problem+json', got '%s'", contentType)
+	}
+
+	var problem map[string]interface{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"type":       "https://errors.example.com/not_found",
+		"title":      "Not Found",
+		"status":     float64(http.StatusNotFound),
+		"detail":     "user not found",
+		"instance":   "/users/42",
+		"code":       "not_found",
+		"request_id": "request-123",
+	}
+	for key, value := range expected {
+		if problem[key] != value {
+			t.Errorf("Expected %s to be %v, got %v", key, value, problem[key])
+		}
+	}
+
+	fields, ok := problem["fields"].(map[string]interface{})
+	if !ok || fields["user_id"] != "42" {
+		t.Errorf("Expected fields with user_id, got %v", problem["fields"])
+	}
+	if ok && fields["api_token"] == "secret" {
+		t.Errorf("Expected api_token to be redacted, got %v", fields["api_token"])
+	}
+}
+
+// TestErrorHandlerAcceptProblem tests selecting problem details with the Accept header
+func TestErrorHandlerAcceptProblem(t *testing.T) {
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Accept", "application/problem+json")
+	recorder := httptest.NewRecorder()
+	newTestRouter(syserr.New(syserr.ForbiddenCode, "forbidden", syserr.F("sql_state", "42501"))).ServeHTTP(recorder, request)
+
+	if contentType := recorder.Header().Get("Content-Type"); contentType != "application/problem+json" {
+		t.Errorf("Expected content type 'application/problem+json', got '%s'", contentType)
+	}
+
+	if recorder.Code != http.StatusForbidden {
+		t.Errorf("Expected status %d, got %d", http.StatusForbidden, recorder.Code)
+	}
+
+	// Fields are only rendered with RenderFields
+	if strings.Contains(recorder.Body.String(), "sql_state") {
+		t.Errorf("Expected no fields, got %s", recorder.Body.String())
+	}
+}
+
+// TestRecovery tests that panics are rendered as internal errors without leaking the panic value
+func TestRecovery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger.Init(&logger.Config{Level: slog.LevelInfo, Output: io.Discard})
+
+	router := gin.New()
+	router.Use(Recovery())
+	router.Use(ErrorHandlerWithConfig(ErrorHandlerConfig{Format: ErrorFormatProblem}))
+	router.GET("/", func(c *gin.Context) {
+		panic("secret connection string")
+	})
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if recorder.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status %d, got %d", http.StatusInternalServerError, recorder.Code)
+	}
+
+	if contentType := recorder.Header().Get("Content-Type"); contentType != "application/problem+json" {
+		t.Errorf("Expected content type 'application/problem+json', got '%s'", contentType)
+	}
+
+	if strings.Contains(recorder.Body.String(), "secret") {
+		t.Errorf("Expected panic value not to be rendered, got %s", recorder.Body.String())
+	}
+}
+
+// TestRecoveryFingerprint tests that panics are reported with their stack and grouped by panic site
+func TestRecoveryFingerprint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logs := loggertest.Install(t)
+	reports := &recordingReporter{}
+	reporter.SetDefault(reports)
+	defer reporter.SetDefault(nil)
+
+	router := gin.New()
+	router.Use(Recovery())
+	router.GET("/first", func(c *gin.Context) {
+		panicFirst()
+	})
+	router.GET("/second", func(c *gin.Context) {
+		panicSecond()
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/first", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/second", nil))
+
+	if len(reports.errs) != 2 {
+		t.Fatalf("Expected 2 reports, got %d", len(reports.errs))
+	}
+
+	first := reporter.Fingerprint(reports.errs[0], 3)
+	second := reporter.Fingerprint(reports.errs[1], 3)
+	if first == second {
+		t.Errorf("Expected different fingerprints for different panic sites, got %s", first)
+	}
+
+	records := logs.Find(loggertest.Code(syserr.InternalCode))
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(records))
+	}
+	if stack, ok := records[0].Attrs["stack"].([]string); !ok || len(stack) == 0 {
+		t.Errorf("Expected the record to carry the stack, got %v", records[0].Attrs["stack"])
+	}
+}
+
+func panicFirst() {
+	panic("first")
+}
+
+func panicSecond() {
+	panic(errors.New("second"))
+}
+
+// TestErrorHandlerBindingErrors tests that gin binding errors are rendered as violations
+func TestErrorHandlerBindingErrors(t *testing.T) {
+	type createUserRequest struct {
+		Email string `json:"email" binding:"required,email"`
+		Age   int    `json:"age" binding:"gte=18"`
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(ErrorHandlerWithConfig(ErrorHandlerConfig{JSONFieldNames: true}))
+	router.POST("/users", func(c *gin.Context) {
+		var request createUserRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.Error(err)
+		}
+	})
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"email":"invalid","age":10}`)))
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, recorder.Code)
+	}
+
+	var body struct {
+		Code    string              `json:"code"`
+		Details []*syserr.Violation `json:"details"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if body.Code != string(syserr.ValidationCode) {
+		t.Errorf("Expected code '%s', got '%s'", syserr.ValidationCode, body.Code)
+	}
+
+	if len(body.Details) != 2 {
+		t.Fatalf("Expected 2 violations, got %d", len(body.Details))
+	}
+
+	if body.Details[0].Field != "email" || body.Details[0].Rule != "email" {
+		t.Errorf("Expected email/email violation, got %s/%s", body.Details[0].Field, body.Details[0].Rule)
+	}
+
+	if body.Details[1].Field != "age" || body.Details[1].Params["param"] != "18" {
+		t.Errorf("Expected age violation with param 18, got %s/%v", body.Details[1].Field, body.Details[1].Params)
+	}
+}
+
+// TestErrorHandlerMalformedBody tests that malformed and empty bodies are rendered as invalid arguments
+func TestErrorHandlerMalformedBody(t *testing.T) {
+	type createUserRequest struct {
+		Email string `json:"email" binding:"required,email"`
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(ErrorHandler())
+	router.POST("/users", func(c *gin.Context) {
+		var request createUserRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.Error(err)
+		}
+	})
+
+	for _, body := range []string{`{"email":`, `{"email" "invalid"}`, ``} {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body)))
+
+		if recorder.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %q, got %d", http.StatusBadRequest, body, recorder.Code)
+		}
+
+		var response struct {
+			Code string `json:"code"`
+		}
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if response.Code != string(syserr.InvalidArgumentCode) {
+			t.Errorf("Expected code '%s' for %q, got '%s'", syserr.InvalidArgumentCode, body, response.Code)
+		}
+	}
+}
+
+type recordingReporter struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+func (r *recordingReporter) Report(_ context.Context, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.errs = append(r.errs, err)
+}
+
+func (r *recordingReporter) Flush(context.Context) error {
+	return nil
+}
+
+func (r *recordingReporter) Close(context.Context) error {
+	return nil
+}
+
+// TestErrorHandlerReportsInternalErrors tests that only internal errors and panics are reported
+func TestErrorHandlerReportsInternalErrors(t *testing.T) {
+	recorder := &recordingReporter{}
+	reporter.SetDefault(recorder)
+	defer reporter.SetDefault(nil)
+
+	errs := []error{
+		syserr.New(syserr.InternalCode, "internal"),
+		errors.New("plain error"),
+		syserr.New(syserr.NotFoundCode, "not found"),
+		syserr.NewValidationError().Add(syserr.V("email", "required", "is required")),
+	}
+	for _, err := range errs {
+		newTestRouter(err).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+
+	router := gin.New()
+	router.Use(Recovery())
+	router.GET("/", func(c *gin.Context) {
+		panic("boom")
+	})
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if len(recorder.errs) != 3 {
+		t.Fatalf("Expected 3 reported errors, got %d: %v", len(recorder.errs), recorder.errs)
+	}
+	if !strings.Contains(recorder.errs[2].Error(), "panic: boom") {
+		t.Errorf("Expected the recovered panic to be reported, got %v", recorder.errs[2])
+	}
+}
+
+// TestErrorHandlerRetryAfter tests that the retry-after hint is sent as Retry-After header
+func TestErrorHandlerRetryAfter(t *testing.T) {
+	syserr.RegisterCode("test_rate_limited", syserr.CodeInfo{HTTPStatus: http.StatusTooManyRequests})
+
+	testCases := []struct {
+		err      error
+		expected string
+	}{
+		{syserr.New("test_rate_limited", "rate limited").WithRetryAfter(1500 * time.Millisecond), "2"},
+		{syserr.Wrap(syserr.New("test_rate_limited", "rate limited").WithRetryAfter(time.Minute), syserr.InternalCode, "failed"), "60"},
+		{syserr.New("test_rate_limited", "rate limited").WithRetryAfter(time.Minute).WithRetryable(false), ""},
+		{syserr.New(syserr.InternalCode, "internal"), ""},
+	}
+
+	for _, testCase := range testCases {
+		recorder := httptest.NewRecorder()
+		newTestRouter(testCase.err).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if actual := recorder.Header().Get("Retry-After"); actual != testCase.expected {
+			t.Errorf("Expected Retry-After %q for %v, got %q", testCase.expected, testCase.err, actual)
+		}
+	}
+}
+
+// TestErrorHandlerLogsErrors tests that handled errors are logged once at the level of their code
+func TestErrorHandlerLogsErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := loggertest.Install(t)
+
+	cause := errors.New("connection reset by peer")
+	errs := []error{
+		syserr.Wrap(cause, syserr.InternalCode, "failed to load order", syserr.F("order_id", 42)),
+		syserr.New(syserr.NotFoundCode, "order not found"),
+	}
+	for _, err := range errs {
+		router := gin.New()
+		router.Use(ErrorHandler())
+		router.GET("/", func(c *gin.Context) {
+			c.Error(err)
+		})
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+
+	records := recorder.Find(loggertest.Code(syserr.InternalCode))
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 record for the internal error, got %d: %v", len(records), records)
+	}
+	if records[0].Level != slog.LevelError {
+		t.Errorf("Expected level %s, got %s", slog.LevelError, records[0].Level)
+	}
+	if !strings.Contains(records[0].Message, cause.Error()) {
+		t.Errorf("Expected the message to contain the cause, got '%s'", records[0].Message)
+	}
+
+	recorder.Expect(loggertest.Code(syserr.InternalCode), loggertest.Attr("order_id", 42))
+	recorder.Expect(loggertest.Level(slog.LevelWarn), loggertest.Code(syserr.NotFoundCode))
+}
+
+// TestErrorHandlerWrappedDecodeErrors tests that decode errors of the application are not taken for binding errors
+func TestErrorHandlerWrappedDecodeErrors(t *testing.T) {
+	testCases := []error{
+		syserr.Wrap(io.ErrUnexpectedEOF, syserr.InternalCode, "downstream read"),
+		fmt.Errorf("read order: %w", io.ErrUnexpectedEOF),
+		fmt.Errorf("decode order: %w", &json.SyntaxError{Offset: 3}),
+	}
+
+	for _, err := range testCases {
+		recorder := httptest.NewRecorder()
+		newTestRouter(err).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if recorder.Code != http.StatusInternalServerError {
+			t.Errorf("Expected status %d for %v, got %d", http.StatusInternalServerError, err, recorder.Code)
+		}
+	}
+}
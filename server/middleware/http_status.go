@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"sync"
+
+	"github.com/duongptryu/gox/syserr"
+)
+
+var (
+	httpStatusCodesMu sync.RWMutex
+	httpStatusCodes   = map[syserr.Code]int{}
+)
+
+// SetHTTPStatusCode overrides the HTTP status code returned for the given error code
+func SetHTTPStatusCode(code syserr.Code, statusCode int) {
+	httpStatusCodesMu.Lock()
+	defer httpStatusCodesMu.Unlock()
+
+	httpStatusCodes[code] = statusCode
+}
+
+// getHTTPStatusCode returns the HTTP status code for the given error code.
+// Overrides take precedence over the status registered in syserr; unknown codes are treated as internal errors.
+func getHTTPStatusCode(code syserr.Code) int {
+	httpStatusCodesMu.RLock()
+	statusCode, ok := httpStatusCodes[code]
+	httpStatusCodesMu.RUnlock()
+
+	if ok {
+		return statusCode
+	}
+
+	return syserr.GetCodeInfo(code).HTTPStatus
+}
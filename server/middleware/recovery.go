@@ -1,20 +1,27 @@
 package middleware
 
 import (
-	"net/http"
+	"fmt"
 
 	"github.com/duongptryu/gox/logger"
-	"github.com/duongptryu/gox/response"
+	"github.com/duongptryu/gox/reporter"
 	"github.com/duongptryu/gox/syserr"
 
 	"github.com/gin-gonic/gin"
 )
 
 func Recovery() gin.HandlerFunc {
-	return gin.CustomRecovery(func(c *gin.Context, err interface{}) {
-		logger.LogError(c.Request.Context(), err.(error))
+	return gin.CustomRecovery(func(c *gin.Context, recovered interface{}) {
+		err, ok := recovered.(error)
+		if !ok {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
 
-		response.NewErrorResponse(string(syserr.InternalCode), "internal server error", err).
-			JSON(c, http.StatusInternalServerError)
+		// Wrap first so that the log and the report carry the stack of the panic
+		err = syserr.Wrap(err, syserr.InternalCode, "panic recovered")
+		logger.LogError(c.Request.Context(), err)
+		reporter.Report(c.Request.Context(), err)
+
+		renderError(c, getErrorHandlerConfig(c), err)
 	})
 }
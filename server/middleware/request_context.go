@@ -25,6 +25,9 @@ func RequestContext() gin.HandlerFunc {
 		}
 		ctx = pkgContext.WithOperationID(ctx, operationID)
 
+		// Keep the W3C trace context so that logs can be correlated with the caller trace
+		ctx = pkgContext.WithTraceParent(ctx, c.GetHeader("traceparent"))
+
 		// Update request context
 		c.Request = c.Request.WithContext(ctx)
 
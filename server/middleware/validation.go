@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/duongptryu/gox/syserr"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+var registerTagNameOnce sync.Once
+
+// registerJSONTagNames makes the gin validator report fields by their json name, so that
+// violations use the names clients send, e.g. "email" rather than "Email".
+// The validator is global, FieldError.Field() returns json names for the whole application.
+func registerJSONTagNames() {
+	registerTagNameOnce.Do(func() {
+		validate, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			return
+		}
+
+		validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			switch name {
+			case "-":
+				return ""
+			case "":
+				return field.Name
+			default:
+				return name
+			}
+		})
+	})
+}
+
+// convertBindingError converts gin binding and validator errors into a syserr.ValidationError,
+// and malformed request bodies into an InvalidArgumentCode error. Other errors are returned unchanged.
+// The error chain is only searched for errors recorded by c.Bind, errors recorded with c.Error must be
+// the binding error itself as returned by ShouldBind, so that e.g. the io.ErrUnexpectedEOF of a truncated
+// downstream response is not taken for an incomplete request body.
+func convertBindingError(err error, errorType gin.ErrorType) error {
+	// Errors carrying a code were already classified by the application
+	var sysErr *syserr.Error
+	if errors.As(err, &sysErr) {
+		return err
+	}
+
+	if errorType&gin.ErrorTypeBind == 0 && !isBindingError(err) {
+		return err
+	}
+
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		result := syserr.NewValidationError()
+		for _, fieldError := range validationErrors {
+			result.Add(convertFieldError(fieldError))
+		}
+		return result
+	}
+
+	var typeError *json.UnmarshalTypeError
+	if errors.As(err, &typeError) {
+		return syserr.NewValidationError(syserr.V(
+			typeError.Field,
+			"type",
+			fmt.Sprintf("must be of type %s", typeError.Type.String()),
+		))
+	}
+
+	var syntaxError *json.SyntaxError
+	if errors.As(err, &syntaxError) {
+		return syserr.Wrap(err, syserr.InvalidArgumentCode, "malformed request body").
+			WithPublicMessage(fmt.Sprintf("malformed JSON at offset %d", syntaxError.Offset))
+	}
+
+	// Decoding an empty or truncated body
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return syserr.Wrap(err, syserr.InvalidArgumentCode, "incomplete request body").
+			WithPublicMessage("request body is empty or incomplete")
+	}
+
+	return err
+}
+
+// isBindingError reports whether err is an error returned by gin binding, without looking at the chain
+func isBindingError(err error) bool {
+	switch err.(type) {
+	case validator.ValidationErrors, *json.UnmarshalTypeError, *json.SyntaxError:
+		return true
+	}
+	return err == io.EOF || err == io.ErrUnexpectedEOF
+}
+
+func convertFieldError(fieldError validator.FieldError) *syserr.Violation {
+	var params []*syserr.Field
+	if fieldError.Param() != "" {
+		params = append(params, syserr.F("param", fieldError.Param()))
+	}
+
+	return syserr.V(
+		getFieldPath(fieldError.Namespace()),
+		fieldError.Tag(),
+		getRuleMessage(fieldError.Tag(), fieldError.Param()),
+		params...,
+	)
+}
+
+// getFieldPath strips the top-level struct name from the namespace, "User.Address.City" becomes "Address.City"
+func getFieldPath(namespace string) string {
+	if index := strings.Index(namespace, "."); index >= 0 {
+		return namespace[index+1:]
+	}
+	return namespace
+}
+
+func getRuleMessage(rule, param string) string {
+	switch rule {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "url":
+		return "must be a valid URL"
+	case "uuid", "uuid4":
+		return "must be a valid UUID"
+	case "min":
+		return fmt.Sprintf("must be at least %s", param)
+	case "max":
+		return fmt.Sprintf("must be at most %s", param)
+	case "len":
+		return fmt.Sprintf("must have length %s", param)
+	case "gt":
+		return fmt.Sprintf("must be greater than %s", param)
+	case "gte":
+		return fmt.Sprintf("must be greater than or equal to %s", param)
+	case "lt":
+		return fmt.Sprintf("must be less than %s", param)
+	case "lte":
+		return fmt.Sprintf("must be less than or equal to %s", param)
+	case "oneof":
+		return fmt.Sprintf("must be one of [%s]", param)
+	default:
+		return fmt.Sprintf("failed on the '%s' rule", rule)
+	}
+}
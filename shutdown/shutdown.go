@@ -0,0 +1,130 @@
+// Package shutdown runs the clean-up hooks of a service, such as flushing logs and closing connections,
+// before it exits.
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTimeout is the time given to Run by the HTTP server shutdown
+	DefaultTimeout = 30 * time.Second
+	// DefaultHookTimeout bounds the time taken by each hook of a registry
+	DefaultHookTimeout = 10 * time.Second
+)
+
+// Hook releases a resource, it should return when the context is done
+type Hook func(ctx context.Context) error
+
+type namedHook struct {
+	name string
+	hook Hook
+}
+
+// Registry holds shutdown hooks and runs them once, in reverse registration order,
+// so that resources are released before the ones they depend on
+type Registry struct {
+	timeout time.Duration
+
+	mu    sync.Mutex
+	hooks []namedHook
+	ran   bool
+}
+
+// NewRegistry creates a registry whose hooks must each complete within the timeout, DefaultHookTimeout when zero
+func NewRegistry(timeout time.Duration) *Registry {
+	if timeout <= 0 {
+		timeout = DefaultHookTimeout
+	}
+
+	return &Registry{timeout: timeout}
+}
+
+// Register adds a hook, hooks registered after Run are ignored
+func (r *Registry) Register(name string, hook Hook) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.ran {
+		return
+	}
+
+	r.hooks = append(r.hooks, namedHook{name: name, hook: hook})
+}
+
+// Run runs the hooks in reverse registration order. Each hook gets the timeout of the registry, a hook still
+// running when it expires is abandoned so that a stuck hook does not starve the next ones. ctx bounds the
+// whole run, the hooks not started when it is done are skipped. A failing hook does not prevent the next
+// ones from running. Only the first call runs the hooks, including calls made by hooks.
+func (r *Registry) Run(ctx context.Context) error {
+	r.mu.Lock()
+	if r.ran {
+		r.mu.Unlock()
+		return nil
+	}
+	r.ran = true
+	hooks := r.hooks
+	r.hooks = nil
+	r.mu.Unlock()
+
+	var errs []error
+	for index := len(hooks) - 1; index >= 0; index-- {
+		hookCtx, cancel := context.WithTimeout(ctx, r.timeout)
+		if err := runHook(hookCtx, hooks[index]); err != nil {
+			errs = append(errs, err)
+		}
+		cancel()
+	}
+
+	return errors.Join(errs...)
+}
+
+// runHook runs the hook and returns when it completes or ctx is done
+func runHook(ctx context.Context, hook namedHook) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("shutdown hook %s: %w", hook.name, err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		done <- hook.hook(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("shutdown hook %s: %w", hook.name, err)
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("shutdown hook %s: %w", hook.name, ctx.Err())
+	}
+}
+
+var defaultRegistry = NewRegistry(DefaultHookTimeout)
+
+// Register adds a hook to the default registry, used by logger.Fatal and the HTTP server shutdown
+func Register(name string, hook Hook) {
+	defaultRegistry.Register(name, hook)
+}
+
+// RegisterCloser adds a hook closing c to the default registry
+func RegisterCloser(name string, c interface{ Close() error }) {
+	Register(name, func(context.Context) error {
+		return c.Close()
+	})
+}
+
+// Run runs the hooks of the default registry
+func Run(ctx context.Context) error {
+	return defaultRegistry.Run(ctx)
+}
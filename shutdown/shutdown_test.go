@@ -0,0 +1,97 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestRegistryRun tests that hooks run once, in reverse order, and that failures do not stop the others
+func TestRegistryRun(t *testing.T) {
+	registry := NewRegistry(time.Second)
+
+	var order []string
+	record := func(name string, err error) Hook {
+		return func(context.Context) error {
+			order = append(order, name)
+			return err
+		}
+	}
+
+	registry.Register("logger", record("logger", nil))
+	registry.Register("reporter", record("reporter", nil))
+	registry.Register("database", record("database", errors.New("connection busy")))
+	registry.Register("messaging", func(ctx context.Context) error {
+		order = append(order, "messaging")
+		// Hooks may trigger a shutdown themselves, e.g. through logger.Fatal
+		return registry.Run(ctx)
+	})
+
+	err := registry.Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "shutdown hook database: connection busy") {
+		t.Errorf("Expected the database hook error, got %v", err)
+	}
+
+	if strings.Join(order, ",") != "messaging,database,reporter,logger" {
+		t.Errorf("Expected hooks in reverse registration order, got %v", order)
+	}
+
+	if err := registry.Run(context.Background()); err != nil || len(order) != 4 {
+		t.Errorf("Expected hooks to run only once, got %v and %v", err, order)
+	}
+}
+
+// TestRegistryTimeout tests that hooks exceeding their timeout are abandoned without starving the next ones
+func TestRegistryTimeout(t *testing.T) {
+	registry := NewRegistry(20 * time.Millisecond)
+
+	loggerFlushed := false
+	registry.Register("logger", func(context.Context) error {
+		loggerFlushed = true
+		return nil
+	})
+	registry.Register("stuck", func(context.Context) error {
+		select {}
+	})
+	registry.Register("panicking", func(context.Context) error {
+		panic("boom")
+	})
+
+	start := time.Now()
+	err := registry.Run(context.Background())
+
+	if time.Since(start) > time.Second {
+		t.Fatalf("Expected Run to return after the timeout")
+	}
+	if err == nil || !strings.Contains(err.Error(), "shutdown hook stuck: context deadline exceeded") ||
+		!strings.Contains(err.Error(), "shutdown hook panicking: panic: boom") {
+		t.Errorf("Unexpected error %v", err)
+	}
+	if !loggerFlushed {
+		t.Errorf("Expected the hooks after a stuck one to run")
+	}
+}
+
+// TestRegistryContext tests that hooks not started when the context is done are skipped
+func TestRegistryContext(t *testing.T) {
+	registry := NewRegistry(time.Second)
+
+	loggerFlushed := false
+	registry.Register("logger", func(context.Context) error {
+		loggerFlushed = true
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := registry.Run(ctx)
+	if err == nil || !strings.Contains(err.Error(), "shutdown hook logger: context canceled") {
+		t.Errorf("Expected the logger hook to be skipped, got %v", err)
+	}
+	if loggerFlushed {
+		t.Errorf("Expected hooks after the context is done to be skipped")
+	}
+}
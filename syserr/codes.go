@@ -11,4 +11,5 @@ const (
 	UnauthorizedCode    Code = "unauthorized"
 	ForbiddenCode       Code = "forbidden"
 	ValidationCode      Code = "validation_error"
+	AbortedCode         Code = "aborted"
 )
@@ -1,20 +1,20 @@
 package syserr
 
 import (
-	"errors"
 	"fmt"
-	"runtime"
-	"strings"
-
-	pkgError "github.com/pkg/errors"
+	"time"
 )
 
 type Error struct {
-	Message      string
-	code         Code
-	Stack        []*ErrorStackItem
-	fields       []*Field
-	WrappedError error
+	Message       string
+	publicMessage string
+	origin        string
+	code          Code
+	stack         *stack
+	fields        []*Field
+	retryable     *bool
+	retryAfter    time.Duration
+	WrappedError  error
 }
 
 type ErrorStackItem struct {
@@ -24,8 +24,8 @@ type ErrorStackItem struct {
 }
 
 type Field struct {
-	Key   string
-	Value any
+	Key   string `json:"key"`
+	Value any    `json:"value"`
 }
 
 func F(key string, value any) *Field {
@@ -36,45 +36,75 @@ func F(key string, value any) *Field {
 }
 
 func New(code Code, message string, fields ...*Field) *Error {
-	stack := extractStackFromGenericError(pkgError.New(message))
 	return &Error{
 		Message: message,
 		code:    code,
 		fields:  fields,
-		Stack:   stack,
+		stack:   captureStack(code, 1),
 	}
 }
 
-func Wrap(err error, code Code, message string, fields ...*Field) *Error {
-	// Try to extract stack trace from the original error first
-	var originalStack []*ErrorStackItem
-	if err != nil {
-		originalStack = extractStackFromGenericError(err)
+// NewPublic creates an error whose message is safe to show to clients
+func NewPublic(code Code, message string, fields ...*Field) *Error {
+	return &Error{
+		Message:       message,
+		publicMessage: message,
+		code:          code,
+		fields:        fields,
+		stack:         captureStack(code, 1),
 	}
+}
+
+func Wrap(err error, code Code, message string, fields ...*Field) *Error {
+	// Try to reuse the stack trace of the original error first
+	originalStack := extractStackFromGenericError(err)
 
-	// If no original stack trace, create a new one
-	if len(originalStack) == 0 {
-		originalStack = extractStackFromGenericError(pkgError.New(message))
+	// If no original stack trace, capture a new one
+	if originalStack == nil {
+		originalStack = captureStack(code, 1)
 	}
 
 	return &Error{
 		Message:      message,
 		code:         code,
 		fields:       fields,
-		Stack:        originalStack,
+		stack:        originalStack,
 		WrappedError: err,
 	}
 }
+
 func WrapAsIs(err error, message string, fields ...*Field) *Error {
-	newError := New(extractCodeFromGenericError(err), message, fields...)
-	newError.WrappedError = err
-	return newError
+	code := GetCodeFromGenericError(err)
+
+	return &Error{
+		Message:      message,
+		code:         code,
+		fields:       fields,
+		stack:        captureStack(code, 1),
+		WrappedError: err,
+	}
 }
 
 func (e *Error) Unwrap() error {
 	return e.WrappedError
 }
 
+// WithPublicMessage sets the message shown to clients, keeping the internal message for logs
+func (e *Error) WithPublicMessage(message string) *Error {
+	e.publicMessage = message
+	return e
+}
+
+// PublicMessage returns the message that is safe to show to clients, or an empty string if none was set
+func (e *Error) PublicMessage() string {
+	return e.publicMessage
+}
+
+// Origin returns the service the error was decoded from, or an empty string for local errors
+func (e *Error) Origin() string {
+	return e.origin
+}
+
 func (e *Error) Code() Code {
 	return e.code
 }
@@ -83,101 +113,28 @@ func (e *Error) Fields() []*Field {
 	return e.fields
 }
 
+// StackTrace returns the captured stack trace, resolving function names and file lines on first use
 func (e *Error) StackTrace() []*ErrorStackItem {
-	return e.Stack
+	return e.stack.Frames()
 }
 
 func (e *Error) Error() string {
+	if e.WrappedError != nil && e.Message == "" {
+		return e.WrappedError.Error()
+	}
 	if e.WrappedError != nil {
 		return fmt.Sprintf("%s: %s", e.Message, e.WrappedError.Error())
 	}
 	return e.Message
 }
 
-func (e *Error) StackFormatted() []string {
-	return formatStack(e.Stack)
+// Stack returns the captured stack trace.
+//
+// Deprecated: Stack was a field before stacks were symbolized lazily, use StackTrace.
+func (e *Error) Stack() []*ErrorStackItem {
+	return e.StackTrace()
 }
 
-func formatStack(stack []*ErrorStackItem) []string {
-	result := make([]string, len(stack))
-
-	for index, stackItem := range stack {
-		result[index] = fmt.Sprintf("%s:%s %s", stackItem.File, stackItem.Line, stackItem.Function)
-	}
-
-	return result
-}
-
-func extractStackFromGenericError(err error) []*ErrorStackItem {
-	stackTrace := extractStackTraceFromGenericError(err)
-
-	result := make([]*ErrorStackItem, len(stackTrace))
-
-	for index, frame := range stackTrace {
-		// Get detailed information from frame
-		pc := uintptr(frame) - 1
-		fn := runtime.FuncForPC(pc)
-		if fn != nil {
-			file, line := fn.FileLine(pc)
-			result[index] = &ErrorStackItem{
-				File:     file,
-				Line:     fmt.Sprintf("%d", line),
-				Function: fn.Name(),
-			}
-		} else {
-			// Fallback to old method if runtime info not available
-			result[index] = &ErrorStackItem{
-				File:     getFrameFilePath(frame),
-				Line:     fmt.Sprintf("%d", frame),
-				Function: fmt.Sprintf("%s", frame),
-			}
-		}
-	}
-
-	return result
-}
-
-type stackTracer interface {
-	StackTrace() pkgError.StackTrace
-}
-
-func extractStackTraceFromGenericError(err error) pkgError.StackTrace {
-	var result pkgError.StackTrace
-
-	var traceableError stackTracer
-	ok := errors.As(err, &traceableError)
-	if ok {
-		result = traceableError.StackTrace()
-	}
-
-	return result
-}
-
-func getFrameFilePath(frame pkgError.Frame) string {
-	frameString := strings.Split(fmt.Sprintf("%+s", frame), "\n\t")
-	return frameString[1]
-}
-
-func extractCodeFromGenericError(err error) Code {
-	if err == nil {
-		return InternalCode
-	}
-
-	for {
-		var sErr *Error
-		if errors.As(err, &sErr) {
-			return sErr.Code()
-		}
-
-		var unwrapError interface{ Unwrap() error }
-		if errors.As(err, &unwrapError) {
-			err = unwrapError.Unwrap()
-			if err == nil {
-				return InternalCode
-			}
-			continue
-		}
-
-		return InternalCode
-	}
+func (e *Error) StackFormatted() []string {
+	return formatStack(e.StackTrace())
 }
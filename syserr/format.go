@@ -0,0 +1,114 @@
+package syserr
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+// LogValue renders the error as a group with its code, message, fields, stack and cause chain.
+// The stack is only rendered at the level that captured it.
+func (e *Error) LogValue() slog.Value {
+	return e.logValue()
+}
+
+func (e *Error) logValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("code", string(e.code)),
+		slog.String("message", e.Message),
+	}
+
+	if e.publicMessage != "" {
+		attrs = append(attrs, slog.String("public_message", e.publicMessage))
+	}
+
+	if fields := redactFields(e.fields); len(fields) > 0 {
+		fieldAttrs := make([]any, len(fields))
+		for index, field := range fields {
+			fieldAttrs[index] = slog.Any(field.Key, field.Value)
+		}
+		attrs = append(attrs, slog.Group("fields", fieldAttrs...))
+	}
+
+	if e.ownsStack() {
+		attrs = append(attrs, slog.Any("stack", e.StackFormatted()))
+	}
+
+	switch cause := e.WrappedError.(type) {
+	case nil:
+	case slog.LogValuer:
+		attrs = append(attrs, slog.Any("cause", cause.LogValue()))
+	default:
+		attrs = append(attrs, slog.String("cause", cause.Error()))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// Format implements fmt.Formatter. %s and %v print the error message, %q the quoted message,
+// and %+v the message followed by the code, fields and stack of every error in the cause chain.
+func (e *Error) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			e.formatVerbose(s)
+			return
+		}
+		_, _ = io.WriteString(s, e.Error())
+	case 's':
+		_, _ = io.WriteString(s, e.Error())
+	case 'q':
+		_, _ = fmt.Fprintf(s, "%q", e.Error())
+	}
+}
+
+func (e *Error) formatVerbose(w io.Writer) {
+	_, _ = io.WriteString(w, e.Error())
+
+	var err error = e
+	for level := 0; err != nil; level++ {
+		sErr, ok := err.(*Error)
+		if !ok {
+			_, _ = fmt.Fprintf(w, "\ncaused by: %s", err.Error())
+
+			// Continue with syserr errors wrapped by foreign errors
+			if !errors.As(err, &sErr) {
+				return
+			}
+			level++
+		}
+
+		if level > 0 {
+			_, _ = fmt.Fprintf(w, "\ncaused by: %s", sErr.Error())
+		}
+
+		_, _ = fmt.Fprintf(w, "\n    code: %s", sErr.code)
+
+		if fields := redactFields(sErr.fields); len(fields) > 0 {
+			_, _ = io.WriteString(w, "\n    fields:")
+			for _, field := range fields {
+				_, _ = fmt.Fprintf(w, " %s=%v", field.Key, field.Value)
+			}
+		}
+
+		if sErr.ownsStack() {
+			_, _ = io.WriteString(w, "\n    stack:")
+			for _, stackItem := range sErr.StackTrace() {
+				_, _ = fmt.Fprintf(w, "\n        %s\n            %s:%s", stackItem.Function, stackItem.File, stackItem.Line)
+			}
+		}
+
+		err = sErr.WrappedError
+	}
+}
+
+// ownsStack reports whether the stack was captured at this level rather than reused from the wrapped error
+func (e *Error) ownsStack() bool {
+	if e.stack == nil {
+		return false
+	}
+
+	var cause *Error
+	return !errors.As(e.WrappedError, &cause) || cause.stack != e.stack
+}
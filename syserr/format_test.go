@@ -0,0 +1,96 @@
+package syserr
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// TestFormat tests the fmt verbs supported by Error
+func TestFormat(t *testing.T) {
+	err := Wrap(New(NotFoundCode, "no rows", F("user_id", 42)), InternalCode, "failed to load user",
+		F("password", "hunter2"))
+
+	if actual := fmt.Sprintf("%v", err); actual != "failed to load user: no rows" {
+		t.Errorf("Unexpected %%v output %q", actual)
+	}
+	if actual := fmt.Sprintf("%s", err); actual != "failed to load user: no rows" {
+		t.Errorf("Unexpected %%s output %q", actual)
+	}
+	if actual := fmt.Sprintf("%q", err); actual != `"failed to load user: no rows"` {
+		t.Errorf("Unexpected %%q output %q", actual)
+	}
+
+	verbose := fmt.Sprintf("%+v", err)
+	for _, expected := range []string{
+		"failed to load user: no rows\n    code: internal",
+		"\ncaused by: no rows\n    code: not_found\n    fields: user_id=42",
+		"    stack:\n        github.com/duongptryu/gox/syserr.TestFormat",
+	} {
+		if !strings.Contains(verbose, expected) {
+			t.Errorf("Expected %%+v output to contain %q, got:\n%s", expected, verbose)
+		}
+	}
+
+	if strings.Contains(verbose, "hunter2") {
+		t.Errorf("Expected fields to be redacted, got:\n%s", verbose)
+	}
+	if strings.Count(verbose, "stack:") != 1 {
+		t.Errorf("Expected the reused stack to be printed once, got:\n%s", verbose)
+	}
+}
+
+// TestFormatForeignCause tests %+v through errors wrapped by foreign errors
+func TestFormatForeignCause(t *testing.T) {
+	err := Wrap(fmt.Errorf("query: %w", New(NotFoundCode, "no rows")), InternalCode, "failed")
+
+	verbose := fmt.Sprintf("%+v", err)
+	if !strings.Contains(verbose, "caused by: query: no rows") || !strings.Contains(verbose, "code: not_found") {
+		t.Errorf("Unexpected %%+v output:\n%s", verbose)
+	}
+
+	verbose = fmt.Sprintf("%+v", Wrap(errors.New("plain"), InternalCode, "failed"))
+	if !strings.HasSuffix(verbose, "caused by: plain") {
+		t.Errorf("Unexpected %%+v output:\n%s", verbose)
+	}
+}
+
+// TestLogValue tests that slog renders the error as a group
+func TestLogValue(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buffer, nil))
+
+	err := Wrap(New(NotFoundCode, "no rows", F("token", "abc")), InternalCode, "failed to load user")
+	logger.Error("request failed", "error", err)
+
+	var record struct {
+		Error struct {
+			Code    string         `json:"code"`
+			Message string         `json:"message"`
+			Stack   []string       `json:"stack"`
+			Cause   map[string]any `json:"cause"`
+		} `json:"error"`
+	}
+	if decodeErr := json.Unmarshal(buffer.Bytes(), &record); decodeErr != nil {
+		t.Fatalf("Failed to decode log record %s: %v", buffer.String(), decodeErr)
+	}
+
+	if record.Error.Code != "internal" || record.Error.Message != "failed to load user" {
+		t.Errorf("Unexpected error group %+v", record.Error)
+	}
+	if len(record.Error.Stack) != 0 {
+		t.Errorf("Expected the reused stack to be rendered at the cause only")
+	}
+
+	cause := record.Error.Cause
+	if cause["code"] != "not_found" || cause["stack"] == nil {
+		t.Errorf("Unexpected cause group %v", cause)
+	}
+	if fields := cause["fields"].(map[string]any); fields["token"] != "[REDACTED]" {
+		t.Errorf("Expected cause fields to be redacted, got %v", fields)
+	}
+}
@@ -0,0 +1,203 @@
+package grpcerr
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/duongptryu/gox/syserr"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Domain identifies the ErrorInfo details produced by this package
+const Domain = "gox.syserr"
+
+var (
+	statusCodesMu sync.RWMutex
+	// statusCodes maps syserr codes to the gRPC codes returned to clients
+	statusCodes = map[syserr.Code]codes.Code{
+		syserr.InternalCode:        codes.Internal,
+		syserr.InvalidArgumentCode: codes.InvalidArgument,
+		syserr.NotFoundCode:        codes.NotFound,
+		syserr.ConflictCode:        codes.AlreadyExists,
+		syserr.UnauthorizedCode:    codes.Unauthenticated,
+		syserr.ForbiddenCode:       codes.PermissionDenied,
+		syserr.ValidationCode:      codes.InvalidArgument,
+		syserr.AbortedCode:         codes.Aborted,
+	}
+)
+
+// grpcCodes maps gRPC codes to syserr codes for statuses without syserr details
+var grpcCodes = map[codes.Code]syserr.Code{
+	codes.InvalidArgument:  syserr.InvalidArgumentCode,
+	codes.NotFound:         syserr.NotFoundCode,
+	codes.AlreadyExists:    syserr.ConflictCode,
+	codes.Unauthenticated:  syserr.UnauthorizedCode,
+	codes.PermissionDenied: syserr.ForbiddenCode,
+	codes.Aborted:          syserr.AbortedCode,
+}
+
+// RegisterGRPCCode sets the gRPC code returned for a syserr code, codes without one are returned as Internal
+func RegisterGRPCCode(code syserr.Code, grpcCode codes.Code) {
+	statusCodesMu.Lock()
+	defer statusCodesMu.Unlock()
+
+	statusCodes[code] = grpcCode
+}
+
+// GetGRPCCode returns the gRPC code registered for a syserr code
+func GetGRPCCode(code syserr.Code) codes.Code {
+	statusCodesMu.RLock()
+	defer statusCodesMu.RUnlock()
+
+	if grpcCode, ok := statusCodes[code]; ok {
+		return grpcCode
+	}
+
+	return codes.Internal
+}
+
+// ToStatus converts an error to a gRPC status.
+// The gRPC code is taken from RegisterGRPCCode and the message is the public message,
+// the syserr code and fields are attached as ErrorInfo, violations as BadRequest and the
+// retry-after hint as RetryInfo details. Field values are redacted with the redact policy.
+func ToStatus(err error) *status.Status {
+	if err == nil {
+		return status.New(codes.OK, "")
+	}
+
+	code := syserr.GetCodeFromGenericError(err)
+	st := status.New(GetGRPCCode(code), syserr.GetPublicMessageFromGenericError(err))
+
+	errorInfo := &errdetails.ErrorInfo{
+		Reason: string(code),
+		Domain: Domain,
+	}
+	if fields := syserr.GetFieldsFromGenericError(err); len(fields) > 0 {
+		errorInfo.Metadata = make(map[string]string, len(fields))
+		for _, field := range fields {
+			errorInfo.Metadata[field.Key] = fmt.Sprint(field.Value)
+		}
+	}
+
+	details := []protoadapt.MessageV1{errorInfo}
+
+	if violations := syserr.GetViolationsFromGenericError(err); len(violations) > 0 {
+		badRequest := &errdetails.BadRequest{}
+		for _, violation := range violations {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       violation.Field,
+				Description: violation.Message,
+				Reason:      violation.Rule,
+			})
+		}
+		details = append(details, badRequest)
+	}
+
+	if retryAfter := syserr.GetRetryAfterFromGenericError(err); retryAfter > 0 {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)})
+	}
+
+	withDetails, detailsErr := st.WithDetails(details...)
+	if detailsErr != nil {
+		return st
+	}
+
+	return withDetails
+}
+
+// FromStatus converts a gRPC status into a syserr error.
+// The syserr code and fields are recovered from ErrorInfo details; field values are strings.
+// Statuses without details are mapped from their gRPC code.
+func FromStatus(st *status.Status) *syserr.Error {
+	if st == nil || st.Code() == codes.OK {
+		return nil
+	}
+
+	code, ok := grpcCodes[st.Code()]
+	if !ok {
+		code = syserr.InternalCode
+	}
+
+	var fields []*syserr.Field
+	var validationErr *syserr.ValidationError
+	var retryAfter time.Duration
+
+	for _, detail := range st.Details() {
+		switch detail := detail.(type) {
+		case *errdetails.ErrorInfo:
+			if detail.GetDomain() != Domain {
+				continue
+			}
+
+			code = syserr.Code(detail.GetReason())
+			fields = convertMetadataToFields(detail.GetMetadata())
+		case *errdetails.BadRequest:
+			validationErr = syserr.NewValidationError()
+			for _, fieldViolation := range detail.GetFieldViolations() {
+				validationErr.Add(syserr.V(fieldViolation.GetField(), fieldViolation.GetReason(), fieldViolation.GetDescription()))
+			}
+		case *errdetails.RetryInfo:
+			retryAfter = detail.GetRetryDelay().AsDuration()
+		}
+	}
+
+	var result *syserr.Error
+	if validationErr != nil {
+		result = syserr.Wrap(validationErr, code, st.Message(), fields...).WithPublicMessage(st.Message())
+	} else {
+		result = syserr.NewPublic(code, st.Message(), fields...)
+	}
+
+	if retryAfter > 0 {
+		result.WithRetryAfter(retryAfter)
+	}
+
+	return result
+}
+
+// FromError converts an error returned by a gRPC call into a syserr error.
+// Errors that do not carry a gRPC status are returned unchanged.
+func FromError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var sErr *syserr.Error
+	if errors.As(err, &sErr) {
+		return err
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	if sErr := FromStatus(st); sErr != nil {
+		return sErr
+	}
+
+	return nil
+}
+
+func convertMetadataToFields(metadata map[string]string) []*syserr.Field {
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make([]*syserr.Field, len(keys))
+	for index, key := range keys {
+		result[index] = syserr.F(key, metadata[key])
+	}
+
+	return result
+}
@@ -0,0 +1,127 @@
+package grpcerr
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/duongptryu/gox/syserr"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestStatusRoundTrip tests converting a syserr error to a gRPC status and back
+func TestStatusRoundTrip(t *testing.T) {
+	err := syserr.Wrap(errors.New("no rows in result set"), syserr.NotFoundCode, "failed to load user",
+		syserr.F("user_id", 42), syserr.F("api_token", "secret")).WithPublicMessage("user not found")
+
+	st := ToStatus(err)
+	if st.Code() != codes.NotFound {
+		t.Errorf("Expected gRPC code %v, got %v", codes.NotFound, st.Code())
+	}
+
+	if st.Message() != "user not found" {
+		t.Errorf("Expected message 'user not found', got '%s'", st.Message())
+	}
+
+	// Simulate the error crossing the wire
+	recovered := FromError(st.Err())
+
+	if code := syserr.GetCodeFromGenericError(recovered); code != syserr.NotFoundCode {
+		t.Errorf("Expected code %s, got %s", syserr.NotFoundCode, code)
+	}
+
+	if message := syserr.GetPublicMessageFromGenericError(recovered); message != "user not found" {
+		t.Errorf("Expected public message 'user not found', got '%s'", message)
+	}
+
+	// Metadata keys are sorted, field values are strings
+	fields := syserr.GetFieldsFromGenericError(recovered)
+	if len(fields) != 2 || fields[1].Key != "user_id" || fields[1].Value != "42" {
+		t.Errorf("Expected user_id field, got %v", fields)
+	}
+	if len(fields) == 2 && fields[0].Value == "secret" {
+		t.Errorf("Expected api_token to be redacted, got %v", fields[0].Value)
+	}
+}
+
+// TestStatusRoundTripViolations tests that validation violations survive the conversion
+func TestStatusRoundTripViolations(t *testing.T) {
+	err := syserr.NewValidationError(
+		syserr.V("email", "email", "must be a valid email address"),
+		syserr.V("age", "gte", "must be greater than or equal to 18"),
+	)
+
+	st := ToStatus(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Errorf("Expected gRPC code %v, got %v", codes.InvalidArgument, st.Code())
+	}
+
+	recovered := FromStatus(st)
+
+	if code := syserr.GetCodeFromGenericError(recovered); code != syserr.ValidationCode {
+		t.Errorf("Expected code %s, got %s", syserr.ValidationCode, code)
+	}
+
+	violations := syserr.GetViolationsFromGenericError(recovered)
+	if len(violations) != 2 {
+		t.Fatalf("Expected 2 violations, got %d", len(violations))
+	}
+
+	if violations[1].Field != "age" || violations[1].Rule != "gte" {
+		t.Errorf("Expected age/gte violation, got %s/%s", violations[1].Field, violations[1].Rule)
+	}
+}
+
+// TestFromStatusWithoutDetails tests mapping plain gRPC statuses to syserr codes
+func TestFromStatusWithoutDetails(t *testing.T) {
+	testCases := []struct {
+		code     codes.Code
+		expected syserr.Code
+	}{
+		{codes.NotFound, syserr.NotFoundCode},
+		{codes.PermissionDenied, syserr.ForbiddenCode},
+		{codes.Unavailable, syserr.InternalCode},
+	}
+
+	for _, testCase := range testCases {
+		err := FromStatus(status.New(testCase.code, "downstream failure"))
+		if code := err.Code(); code != testCase.expected {
+			t.Errorf("Expected code %s for %v, got %s", testCase.expected, testCase.code, code)
+		}
+	}
+
+	if err := FromStatus(status.New(codes.OK, "")); err != nil {
+		t.Errorf("Expected nil error for OK status, got %v", err)
+	}
+}
+
+// TestStatusRoundTripRetryAfter tests that the retry-after hint travels as RetryInfo
+func TestStatusRoundTripRetryAfter(t *testing.T) {
+	err := syserr.NewPublic(syserr.AbortedCode, "transaction aborted").WithRetryAfter(2 * time.Second)
+
+	recovered := FromError(ToStatus(err).Err())
+
+	if retryAfter := syserr.GetRetryAfterFromGenericError(recovered); retryAfter != 2*time.Second {
+		t.Errorf("Expected retry-after 2s, got %v", retryAfter)
+	}
+	if code := syserr.GetCodeFromGenericError(recovered); code != syserr.AbortedCode {
+		t.Errorf("Expected code %s, got %s", syserr.AbortedCode, code)
+	}
+}
+
+// TestRegisterGRPCCode tests mapping application-defined codes to gRPC codes
+func TestRegisterGRPCCode(t *testing.T) {
+	code := syserr.Code("payment_declined")
+
+	if st := ToStatus(syserr.New(code, "card declined")); st.Code() != codes.Internal {
+		t.Errorf("Expected gRPC code %v for an unmapped code, got %v", codes.Internal, st.Code())
+	}
+
+	RegisterGRPCCode(code, codes.FailedPrecondition)
+
+	if st := ToStatus(syserr.New(code, "card declined")); st.Code() != codes.FailedPrecondition {
+		t.Errorf("Expected gRPC code %v, got %v", codes.FailedPrecondition, st.Code())
+	}
+}
@@ -1,6 +1,10 @@
 package syserr
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/duongptryu/gox/redact"
+)
 
 func GetStackFormattedFromGenericError(err error) []string {
 	var sysErr *Error
@@ -8,55 +12,93 @@ func GetStackFormattedFromGenericError(err error) []string {
 		return sysErr.StackFormatted()
 	}
 
-	return formatStack(extractStackFromGenericError(err))
+	return formatStack(extractStackFromGenericError(err).Frames())
 }
 
+// GetCodeFromGenericError returns the code of the first error in the chain that carries one.
+// Errors without a code are treated as internal errors.
 func GetCodeFromGenericError(err error) Code {
 	if err == nil {
 		return InternalCode
 	}
 
-	for {
+	var codeError interface{ Code() Code }
+	if errors.As(err, &codeError) {
+		return codeError.Code()
+	}
+
+	return InternalCode
+}
+
+// GetPublicMessageFromGenericError returns the outermost public message in the error chain.
+// If none was set, the default message registered for the error code is returned.
+func GetPublicMessageFromGenericError(err error) string {
+	if message := findPublicMessage(err); message != "" {
+		return message
+	}
+
+	return GetCodeInfoFromGenericError(err).Message
+}
+
+func findPublicMessage(err error) string {
+	for err != nil {
 		var sErr *Error
-		if errors.As(err, &sErr) {
-			return sErr.Code()
+		if !errors.As(err, &sErr) {
+			return ""
 		}
 
-		var unwrapError interface{ Unwrap() error }
-		if errors.As(err, &unwrapError) {
-			err = unwrapError.Unwrap()
-			if err == nil {
-				return InternalCode
-			}
-			continue
+		if sErr.PublicMessage() != "" {
+			return sErr.PublicMessage()
 		}
-		return InternalCode
+
+		err = sErr.Unwrap()
 	}
+
+	return ""
 }
 
+// GetFieldsFromGenericError returns the fields of every syserr error in the chain, outermost first.
+// Values are redacted with the current redact policy.
 func GetFieldsFromGenericError(err error) []*Field {
 	var result []*Field
 
-	for {
-		if err == nil {
+	for err != nil {
+		var sErr *Error
+		if !errors.As(err, &sErr) {
 			return result
 		}
 
-		var sErr *Error
-		if errors.As(err, &sErr) {
-			result = append(result, sErr.Fields()...)
-		}
+		result = append(result, redactFields(sErr.Fields())...)
+		err = sErr.Unwrap()
+	}
 
-		var unwrapError interface{ Unwrap() error }
-		if errors.As(err, &unwrapError) {
-			err = unwrapError.Unwrap()
-			if err == nil {
-				return result
-			}
-			continue
-		}
-		return result
+	return result
+}
+
+// redactFields returns copies of the fields with their values redacted
+func redactFields(fields []*Field) []*Field {
+	if len(fields) == 0 {
+		return nil
 	}
+
+	policy := redact.GetPolicy()
+	result := make([]*Field, len(fields))
+
+	for index, field := range fields {
+		result[index] = F(field.Key, policy.Value(field.Key, field.Value))
+	}
+
+	return result
+}
+
+// GetViolationsFromGenericError returns the field-level violations of the first validation error in the chain
+func GetViolationsFromGenericError(err error) []*Violation {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr.Violations()
+	}
+
+	return nil
 }
 
 func UnwrapError(err error) error {
@@ -0,0 +1,31 @@
+package syserr
+
+import (
+	"testing"
+
+	"github.com/duongptryu/gox/redact"
+)
+
+// TestGetFieldsFromGenericErrorRedaction tests that extracted fields are redacted
+func TestGetFieldsFromGenericErrorRedaction(t *testing.T) {
+	err := Wrap(New(UnauthorizedCode, "login failed", F("password", "hunter2")), InternalCode, "failed",
+		F("user_id", 42), F("session", redact.Secret("abc")))
+
+	fields := GetFieldsFromGenericError(err)
+
+	expected := map[string]any{
+		"user_id":  42,
+		"session":  redact.Redacted(redact.Placeholder),
+		"password": redact.Redacted(redact.Placeholder),
+	}
+	for _, field := range fields {
+		if field.Value != expected[field.Key] {
+			t.Errorf("Expected %s=%v, got %v", field.Key, expected[field.Key], field.Value)
+		}
+	}
+
+	// The error keeps the original values
+	if inner := err.Unwrap().(*Error); inner.Fields()[0].Value != "hunter2" {
+		t.Errorf("Expected the original field value to be kept, got %v", inner.Fields()[0].Value)
+	}
+}
@@ -0,0 +1,113 @@
+package syserr
+
+import (
+	"log/slog"
+	"net/http"
+	"sync"
+)
+
+// CodeInfo describes the behaviour attached to an error code
+type CodeInfo struct {
+	// HTTPStatus is the HTTP status code returned to clients
+	HTTPStatus int
+	// Message is the default message that is safe to show to clients
+	Message string
+	// LogLevel is the level used when logging errors with this code
+	LogLevel slog.Level
+	// Retryable reports whether an operation failing with this code may succeed when retried
+	Retryable bool
+	// DisableStack turns off stack capture for expected errors with this code
+	DisableStack bool
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[Code]CodeInfo{
+		InternalCode: {
+			HTTPStatus: http.StatusInternalServerError,
+			Message:    "internal server error",
+			LogLevel:   slog.LevelError,
+			Retryable:  true,
+		},
+		InvalidArgumentCode: {
+			HTTPStatus: http.StatusBadRequest,
+			Message:    "invalid argument",
+			LogLevel:   slog.LevelWarn,
+		},
+		NotFoundCode: {
+			HTTPStatus: http.StatusNotFound,
+			Message:    "resource not found",
+			LogLevel:   slog.LevelWarn,
+		},
+		ConflictCode: {
+			HTTPStatus: http.StatusConflict,
+			Message:    "resource conflict",
+			LogLevel:   slog.LevelWarn,
+		},
+		UnauthorizedCode: {
+			HTTPStatus: http.StatusUnauthorized,
+			Message:    "unauthorized",
+			LogLevel:   slog.LevelWarn,
+		},
+		ForbiddenCode: {
+			HTTPStatus: http.StatusForbidden,
+			Message:    "forbidden",
+			LogLevel:   slog.LevelWarn,
+		},
+		ValidationCode: {
+			HTTPStatus: http.StatusBadRequest,
+			Message:    "validation failed",
+			LogLevel:   slog.LevelWarn,
+		},
+		AbortedCode: {
+			HTTPStatus: http.StatusConflict,
+			Message:    "operation aborted, please retry",
+			LogLevel:   slog.LevelWarn,
+			Retryable:  true,
+		},
+	}
+)
+
+// RegisterCode registers an application-defined error code together with its behaviour.
+// Registering an existing code replaces its information. Zero HTTP status and message are
+// filled from InternalCode. The gRPC code is registered with grpcerr.RegisterGRPCCode.
+func RegisterCode(code Code, info CodeInfo) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	internal := registry[InternalCode]
+	if info.HTTPStatus == 0 {
+		info.HTTPStatus = internal.HTTPStatus
+	}
+	if info.Message == "" {
+		info.Message = internal.Message
+	}
+
+	registry[code] = info
+}
+
+// LookupCode returns the information registered for the code and whether it is registered
+func LookupCode(code Code) (CodeInfo, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	info, ok := registry[code]
+	return info, ok
+}
+
+// GetCodeInfo returns the information registered for the code.
+// Unknown codes fall back to the InternalCode information.
+func GetCodeInfo(code Code) CodeInfo {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	if info, ok := registry[code]; ok {
+		return info
+	}
+
+	return registry[InternalCode]
+}
+
+func GetCodeInfoFromGenericError(err error) CodeInfo {
+	return GetCodeInfo(GetCodeFromGenericError(err))
+}
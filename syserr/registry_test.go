@@ -0,0 +1,81 @@
+package syserr
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"testing"
+)
+
+// TestRegisterCode tests registering an application-defined code
+func TestRegisterCode(t *testing.T) {
+	code := Code("payment_declined")
+	RegisterCode(code, CodeInfo{
+		HTTPStatus: http.StatusPaymentRequired,
+		Message:    "payment declined",
+		LogLevel:   slog.LevelInfo,
+		Retryable:  false,
+	})
+
+	info, ok := LookupCode(code)
+	if !ok {
+		t.Fatal("Expected code to be registered")
+	}
+
+	if info.HTTPStatus != http.StatusPaymentRequired {
+		t.Errorf("Expected HTTP status %d, got %d", http.StatusPaymentRequired, info.HTTPStatus)
+	}
+
+	if IsRetryable(New(code, "card declined")) {
+		t.Error("Expected payment_declined to be permanent")
+	}
+}
+
+// TestRegisterCodeDefaults tests that zero values are filled from InternalCode
+func TestRegisterCodeDefaults(t *testing.T) {
+	code := Code("quota_exceeded")
+	RegisterCode(code, CodeInfo{Retryable: true})
+
+	info := GetCodeInfo(code)
+	internal := GetCodeInfo(InternalCode)
+
+	if info.HTTPStatus != internal.HTTPStatus {
+		t.Errorf("Expected HTTP status %d, got %d", internal.HTTPStatus, info.HTTPStatus)
+	}
+
+	if info.Message != internal.Message {
+		t.Errorf("Expected message '%s', got '%s'", internal.Message, info.Message)
+	}
+}
+
+// TestGetCodeInfoUnknownCode tests that unknown codes fall back to InternalCode
+func TestGetCodeInfoUnknownCode(t *testing.T) {
+	if _, ok := LookupCode(Code("never_registered")); ok {
+		t.Error("Expected code not to be registered")
+	}
+
+	info := GetCodeInfo(Code("never_registered"))
+	if info != GetCodeInfo(InternalCode) {
+		t.Errorf("Expected InternalCode info, got %+v", info)
+	}
+}
+
+// TestIsRetryable tests retryability of generic and wrapped errors
+func TestIsRetryable(t *testing.T) {
+	testCases := []struct {
+		err      error
+		expected bool
+	}{
+		{nil, false},
+		{errors.New("plain error"), true},
+		{New(InternalCode, "internal"), true},
+		{New(ValidationCode, "validation"), false},
+		{Wrap(errors.New("no rows"), NotFoundCode, "user not found"), false},
+	}
+
+	for _, testCase := range testCases {
+		if actual := IsRetryable(testCase.err); actual != testCase.expected {
+			t.Errorf("Expected IsRetryable(%v) to be %v, got %v", testCase.err, testCase.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package syserr
+
+import (
+	"errors"
+	"time"
+)
+
+// WithRetryable classifies the error as retryable or permanent, overriding the default of its code
+func (e *Error) WithRetryable(retryable bool) *Error {
+	e.retryable = &retryable
+	return e
+}
+
+// WithRetryAfter sets how long callers should wait before retrying.
+// Unless classified otherwise with WithRetryable, the error becomes retryable.
+func (e *Error) WithRetryAfter(retryAfter time.Duration) *Error {
+	e.retryAfter = retryAfter
+	return e
+}
+
+// Retryable returns the classification set with WithRetryable and whether one was set
+func (e *Error) Retryable() (bool, bool) {
+	if e.retryable == nil {
+		return false, false
+	}
+	return *e.retryable, true
+}
+
+// RetryAfter returns the duration set with WithRetryAfter, or zero if none was set
+func (e *Error) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
+// IsRetryable reports whether the operation that returned err may succeed when retried.
+// The outermost classification set with WithRetryable or WithRetryAfter wins,
+// otherwise the retryability registered for the error code is used.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	for current := err; current != nil; {
+		var sErr *Error
+		if !errors.As(current, &sErr) {
+			break
+		}
+
+		if retryable, ok := sErr.Retryable(); ok {
+			return retryable
+		}
+		if sErr.retryAfter > 0 {
+			return true
+		}
+
+		current = sErr.Unwrap()
+	}
+
+	return GetCodeInfoFromGenericError(err).Retryable
+}
+
+// IsPermanent reports whether err is an error that will not succeed when retried
+func IsPermanent(err error) bool {
+	return err != nil && !IsRetryable(err)
+}
+
+// GetRetryAfterFromGenericError returns the outermost retry-after duration in the error chain,
+// or zero if none was set or the error is permanent
+func GetRetryAfterFromGenericError(err error) time.Duration {
+	if !IsRetryable(err) {
+		return 0
+	}
+
+	for current := err; current != nil; {
+		var sErr *Error
+		if !errors.As(current, &sErr) {
+			break
+		}
+
+		if sErr.retryAfter > 0 {
+			return sErr.retryAfter
+		}
+
+		current = sErr.Unwrap()
+	}
+
+	return 0
+}
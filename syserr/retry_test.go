@@ -0,0 +1,58 @@
+package syserr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// TestRetryClassification tests explicit classification against code defaults
+func TestRetryClassification(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"code default retryable", New(InternalCode, "timeout"), true},
+		{"code default permanent", New(ValidationCode, "invalid"), false},
+		{"marked permanent", New(InternalCode, "corrupt").WithRetryable(false), false},
+		{"marked retryable", New(ConflictCode, "stale version").WithRetryable(true), true},
+		{"retry after", New(ConflictCode, "locked").WithRetryAfter(time.Second), true},
+		{"retry after marked permanent", New(ConflictCode, "locked").WithRetryAfter(time.Second).WithRetryable(false), false},
+		{"wrapped classification", Wrap(New(InternalCode, "corrupt").WithRetryable(false), InternalCode, "failed"), false},
+		{"outermost wins", Wrap(New(InternalCode, "corrupt").WithRetryable(false), InternalCode, "failed").WithRetryable(true), true},
+		{"foreign wrapper", fmt.Errorf("handler: %w", New(InternalCode, "corrupt").WithRetryable(false)), false},
+		{"plain error", errors.New("plain"), true},
+		{"nil", nil, false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if actual := IsRetryable(testCase.err); actual != testCase.expected {
+				t.Errorf("Expected IsRetryable to be %v, got %v", testCase.expected, actual)
+			}
+			if testCase.err != nil && IsPermanent(testCase.err) == testCase.expected {
+				t.Errorf("Expected IsPermanent to be %v", !testCase.expected)
+			}
+		})
+	}
+}
+
+// TestGetRetryAfterFromGenericError tests the retry-after lookup through wrapped errors
+func TestGetRetryAfterFromGenericError(t *testing.T) {
+	inner := New(InternalCode, "rate limited").WithRetryAfter(time.Second)
+
+	if actual := GetRetryAfterFromGenericError(Wrap(inner, InternalCode, "failed")); actual != time.Second {
+		t.Errorf("Expected 1s, got %v", actual)
+	}
+	if actual := GetRetryAfterFromGenericError(Wrap(inner, InternalCode, "failed").WithRetryAfter(time.Minute)); actual != time.Minute {
+		t.Errorf("Expected the outermost hint, got %v", actual)
+	}
+	if actual := GetRetryAfterFromGenericError(Wrap(inner, InternalCode, "failed").WithRetryable(false)); actual != 0 {
+		t.Errorf("Expected no hint for permanent errors, got %v", actual)
+	}
+	if actual := GetRetryAfterFromGenericError(errors.New("plain")); actual != 0 {
+		t.Errorf("Expected no hint, got %v", actual)
+	}
+}
@@ -0,0 +1,157 @@
+package syserr
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	pkgError "github.com/pkg/errors"
+)
+
+const (
+	defaultStackDepth = 32
+	// maxInlineStackDepth is the depth captured without an intermediate heap buffer
+	maxInlineStackDepth = 64
+)
+
+// StackConfig controls how stack traces are captured
+type StackConfig struct {
+	// Depth is the maximum number of frames captured. Zero uses the default of 32 frames.
+	Depth int
+	// Skip is the number of additional frames skipped above the syserr constructor,
+	// useful when errors are created through helper functions
+	Skip int
+	// Disabled turns off stack capture for every code
+	Disabled bool
+}
+
+var stackConfig atomic.Pointer[StackConfig]
+
+func init() {
+	stackConfig.Store(&StackConfig{Depth: defaultStackDepth})
+}
+
+// SetStackConfig sets the global stack capture configuration.
+// Capture can also be disabled per code with CodeInfo.DisableStack.
+func SetStackConfig(cfg StackConfig) {
+	if cfg.Depth <= 0 {
+		cfg.Depth = defaultStackDepth
+	}
+
+	stackConfig.Store(&cfg)
+}
+
+// stack holds raw program counters, symbolized lazily on first access
+type stack struct {
+	pcs    []uintptr
+	once   sync.Once
+	frames []*ErrorStackItem
+}
+
+// captureStack records the stack of the caller of the function calling captureStack,
+// skip being the number of frames between captureStack and the syserr constructor
+func captureStack(code Code, skip int) *stack {
+	cfg := stackConfig.Load()
+	if cfg.Disabled || GetCodeInfo(code).DisableStack {
+		return nil
+	}
+
+	// Skip runtime.Callers, captureStack and the syserr constructor
+	skip += 2 + cfg.Skip
+
+	if cfg.Depth <= maxInlineStackDepth {
+		var buffer [maxInlineStackDepth]uintptr
+		count := runtime.Callers(skip, buffer[:cfg.Depth])
+		if count == 0 {
+			return nil
+		}
+		return &stack{pcs: append([]uintptr(nil), buffer[:count]...)}
+	}
+
+	pcs := make([]uintptr, cfg.Depth)
+	count := runtime.Callers(skip, pcs)
+	if count == 0 {
+		return nil
+	}
+	return &stack{pcs: pcs[:count]}
+}
+
+// Frames returns the symbolized frames of the stack
+func (s *stack) Frames() []*ErrorStackItem {
+	if s == nil {
+		return nil
+	}
+
+	s.once.Do(func() {
+		s.frames = make([]*ErrorStackItem, 0, len(s.pcs))
+
+		frames := runtime.CallersFrames(s.pcs)
+		for {
+			frame, more := frames.Next()
+			// An empty stack yields a zero frame
+			if frame.PC == 0 {
+				break
+			}
+
+			s.frames = append(s.frames, &ErrorStackItem{
+				File:     frame.File,
+				Line:     strconv.Itoa(frame.Line),
+				Function: frame.Function,
+			})
+
+			if !more {
+				break
+			}
+		}
+	})
+
+	return s.frames
+}
+
+func formatStack(stack []*ErrorStackItem) []string {
+	result := make([]string, len(stack))
+
+	for index, stackItem := range stack {
+		result[index] = fmt.Sprintf("%s:%s %s", stackItem.File, stackItem.Line, stackItem.Function)
+	}
+
+	return result
+}
+
+type stackTracer interface {
+	StackTrace() pkgError.StackTrace
+}
+
+// extractStackFromGenericError returns the stack of the first syserr or github.com/pkg/errors
+// error in the chain, or nil if there is none
+func extractStackFromGenericError(err error) *stack {
+	if err == nil {
+		return nil
+	}
+
+	var sErr *Error
+	if errors.As(err, &sErr) && sErr.stack != nil {
+		return sErr.stack
+	}
+
+	var traceableError stackTracer
+	if !errors.As(err, &traceableError) {
+		return nil
+	}
+
+	// pkg/errors frames hold the same return addresses as runtime.Callers
+	stackTrace := traceableError.StackTrace()
+	if len(stackTrace) == 0 {
+		return nil
+	}
+
+	pcs := make([]uintptr, len(stackTrace))
+	for index, frame := range stackTrace {
+		pcs[index] = uintptr(frame)
+	}
+
+	return &stack{pcs: pcs}
+}
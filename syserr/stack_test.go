@@ -0,0 +1,176 @@
+package syserr
+
+import (
+	"errors"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	pkgError "github.com/pkg/errors"
+)
+
+const cacheMissCode Code = "test_cache_miss"
+
+var errPlain = errors.New("plain")
+
+func newFromHelper() *Error {
+	return New(InternalCode, "from helper")
+}
+
+// TestStackStartsAtCaller tests that the stack starts at the caller of each constructor
+func TestStackStartsAtCaller(t *testing.T) {
+	constructors := map[string]func() *Error{
+		"New":       func() *Error { return New(InternalCode, "boom") },
+		"NewPublic": func() *Error { return NewPublic(NotFoundCode, "boom") },
+		"Wrap":      func() *Error { return Wrap(errPlain, InternalCode, "boom") },
+		"WrapAsIs":  func() *Error { return WrapAsIs(errPlain, "boom") },
+	}
+
+	for name, constructor := range constructors {
+		t.Run(name, func(t *testing.T) {
+			frames := constructor().StackTrace()
+			if len(frames) == 0 {
+				t.Fatal("Expected stack frames")
+			}
+			if !strings.Contains(frames[0].Function, "TestStackStartsAtCaller") {
+				t.Errorf("Expected the first frame to be the calling test function, got %s", frames[0].Function)
+			}
+		})
+	}
+}
+
+// TestStackConfigSkip tests skipping the frames of helper functions
+func TestStackConfigSkip(t *testing.T) {
+	defer SetStackConfig(StackConfig{})
+	SetStackConfig(StackConfig{Skip: 1})
+
+	frames := newFromHelper().StackTrace()
+	if len(frames) == 0 || !strings.HasSuffix(frames[0].Function, "TestStackConfigSkip") {
+		t.Errorf("Expected the first frame to be the caller of the helper, got %+v", frames)
+	}
+}
+
+// TestStackConfigDepth tests limiting the number of captured frames
+func TestStackConfigDepth(t *testing.T) {
+	defer SetStackConfig(StackConfig{})
+	SetStackConfig(StackConfig{Depth: 2})
+
+	if frames := New(InternalCode, "boom").StackTrace(); len(frames) != 2 {
+		t.Errorf("Expected 2 frames, got %d", len(frames))
+	}
+}
+
+// TestStackConfigDisabled tests turning off stack capture globally
+func TestStackConfigDisabled(t *testing.T) {
+	defer SetStackConfig(StackConfig{})
+	SetStackConfig(StackConfig{Disabled: true})
+
+	err := New(InternalCode, "boom")
+	if frames := err.StackTrace(); frames != nil {
+		t.Errorf("Expected no stack trace, got %v", frames)
+	}
+	if formatted := err.StackFormatted(); len(formatted) != 0 {
+		t.Errorf("Expected no formatted stack, got %v", formatted)
+	}
+}
+
+// TestStackDisabledPerCode tests turning off stack capture for a code
+func TestStackDisabledPerCode(t *testing.T) {
+	RegisterCode(cacheMissCode, CodeInfo{DisableStack: true})
+
+	if frames := New(cacheMissCode, "miss").StackTrace(); frames != nil {
+		t.Errorf("Expected no stack trace, got %v", frames)
+	}
+	if frames := New(InternalCode, "boom").StackTrace(); len(frames) == 0 {
+		t.Error("Expected stack frames for codes without DisableStack")
+	}
+}
+
+// TestWrapReusesStack tests that wrapping keeps the stack of syserr and pkg/errors errors
+func TestWrapReusesStack(t *testing.T) {
+	inner := New(InternalCode, "inner")
+	wrapped := Wrap(inner, InternalCode, "outer")
+
+	if wrapped.stack != inner.stack {
+		t.Error("Expected Wrap to reuse the stack of the wrapped error")
+	}
+
+	_, file, line, _ := runtime.Caller(0)
+	pkgErr := pkgError.New("pkg")
+	frames := Wrap(pkgErr, InternalCode, "outer").StackTrace()
+	if len(frames) == 0 || frames[0].File != file || frames[0].Line != strconv.Itoa(line+1) {
+		t.Errorf("Expected the first frame to be %s:%d, got %+v", file, line+1, frames)
+	}
+}
+
+// TestEmptyStack tests that an empty capture yields no frames and lets Wrap capture its own stack
+func TestEmptyStack(t *testing.T) {
+	SetStackConfig(StackConfig{Skip: 1000})
+	inner := New(InternalCode, "inner")
+	SetStackConfig(StackConfig{})
+
+	if inner.stack != nil {
+		t.Errorf("Expected no stack when every frame is skipped, got %+v", inner.StackTrace())
+	}
+
+	if frames := (&stack{}).Frames(); len(frames) != 0 {
+		t.Errorf("Expected no frames for empty pcs, got %+v", frames)
+	}
+
+	frames := Wrap(inner, InternalCode, "outer").StackTrace()
+	if len(frames) == 0 || !strings.HasSuffix(frames[0].Function, "TestEmptyStack") {
+		t.Errorf("Expected Wrap to capture a stack starting at TestEmptyStack, got %+v", frames)
+	}
+}
+
+// legacyNew reproduces the previous capture strategy: a pkg/errors error symbolized eagerly
+func legacyNew(code Code, message string) *Error {
+	stackTrace := pkgError.New(message).(stackTracer).StackTrace()
+	frames := make([]*ErrorStackItem, len(stackTrace))
+	for index, frame := range stackTrace {
+		pc := uintptr(frame) - 1
+		fn := runtime.FuncForPC(pc)
+		file, line := fn.FileLine(pc)
+		frames[index] = &ErrorStackItem{File: file, Line: strconv.Itoa(line), Function: fn.Name()}
+	}
+
+	err := &Error{Message: message, code: code, stack: &stack{frames: frames}}
+	err.stack.once.Do(func() {})
+	return err
+}
+
+// BenchmarkNew benchmarks creating an error with lazy stack capture
+func BenchmarkNew(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = New(InternalCode, "boom")
+	}
+}
+
+// BenchmarkNewLegacy benchmarks creating an error with the previous eager capture
+func BenchmarkNewLegacy(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = legacyNew(InternalCode, "boom")
+	}
+}
+
+// BenchmarkNewStackDisabled benchmarks creating an error without stack capture
+func BenchmarkNewStackDisabled(b *testing.B) {
+	defer SetStackConfig(StackConfig{})
+	SetStackConfig(StackConfig{Disabled: true})
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = New(InternalCode, "boom")
+	}
+}
+
+// BenchmarkNewAndStackTrace benchmarks creating an error and symbolizing its stack
+func BenchmarkNewAndStackTrace(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = New(InternalCode, "boom").StackTrace()
+	}
+}
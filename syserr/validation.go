@@ -0,0 +1,108 @@
+package syserr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Violation describes a single field-level validation failure
+type Violation struct {
+	Field   string                 `json:"field"`
+	Rule    string                 `json:"rule"`
+	Message string                 `json:"message"`
+	Params  map[string]interface{} `json:"params,omitempty"`
+}
+
+// V creates a new violation of the rule for the field at the given path
+func V(field, rule, message string, params ...*Field) *Violation {
+	violation := &Violation{
+		Field:   field,
+		Rule:    rule,
+		Message: message,
+	}
+
+	if len(params) > 0 {
+		violation.Params = make(map[string]interface{}, len(params))
+		for _, param := range params {
+			violation.Params[param.Key] = param.Value
+		}
+	}
+
+	return violation
+}
+
+func (v *Violation) Error() string {
+	if v.Field == "" {
+		return v.Message
+	}
+	return fmt.Sprintf("%s: %s", v.Field, v.Message)
+}
+
+// Is reports whether target is a violation of the same field and rule.
+// An empty field or rule in target matches any value.
+func (v *Violation) Is(target error) bool {
+	var targetViolation *Violation
+	if !errors.As(target, &targetViolation) {
+		return false
+	}
+
+	return (targetViolation.Field == "" || targetViolation.Field == v.Field) &&
+		(targetViolation.Rule == "" || targetViolation.Rule == v.Rule)
+}
+
+// ValidationError aggregates field-level violations into a single error with ValidationCode
+type ValidationError struct {
+	violations []*Violation
+}
+
+// NewValidationError creates a validation error with the given violations
+func NewValidationError(violations ...*Violation) *ValidationError {
+	return &ValidationError{violations: violations}
+}
+
+// Add appends violations to the error
+func (e *ValidationError) Add(violations ...*Violation) *ValidationError {
+	e.violations = append(e.violations, violations...)
+	return e
+}
+
+// HasViolations reports whether any violation was added
+func (e *ValidationError) HasViolations() bool {
+	return len(e.violations) > 0
+}
+
+// ErrOrNil returns the error if it has violations, nil otherwise
+func (e *ValidationError) ErrOrNil() error {
+	if !e.HasViolations() {
+		return nil
+	}
+	return e
+}
+
+func (e *ValidationError) Violations() []*Violation {
+	return e.violations
+}
+
+func (e *ValidationError) Code() Code {
+	return ValidationCode
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.violations))
+	for index, violation := range e.violations {
+		messages[index] = violation.Error()
+	}
+
+	return fmt.Sprintf("validation failed: %s", strings.Join(messages, "; "))
+}
+
+// Unwrap returns the violations so that errors.Is and errors.As can match them
+func (e *ValidationError) Unwrap() []error {
+	result := make([]error, len(e.violations))
+	for index, violation := range e.violations {
+		result[index] = violation
+	}
+
+	return result
+}
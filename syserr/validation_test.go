@@ -0,0 +1,61 @@
+package syserr
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestValidationError tests aggregating violations and matching them with errors.Is/As
+func TestValidationError(t *testing.T) {
+	validationErr := NewValidationError().
+		Add(V("email", "email", "must be a valid email address")).
+		Add(V("age", "gte", "must be greater than or equal to 18", F("param", 18)))
+
+	err := Wrap(validationErr, ValidationCode, "invalid create user request")
+
+	if code := GetCodeFromGenericError(validationErr); code != ValidationCode {
+		t.Errorf("Expected code %s, got %s", ValidationCode, code)
+	}
+
+	if code := GetCodeFromGenericError(err); code != ValidationCode {
+		t.Errorf("Expected code %s, got %s", ValidationCode, code)
+	}
+
+	if !errors.Is(err, &Violation{Field: "age"}) {
+		t.Error("Expected error to match the age violation")
+	}
+
+	if errors.Is(err, &Violation{Field: "name", Rule: "required"}) {
+		t.Error("Expected error not to match the name violation")
+	}
+
+	var target *ValidationError
+	if !errors.As(err, &target) {
+		t.Fatal("Expected error to be a ValidationError")
+	}
+
+	violations := GetViolationsFromGenericError(err)
+	if len(violations) != 2 {
+		t.Fatalf("Expected 2 violations, got %d", len(violations))
+	}
+
+	if violations[1].Params["param"] != 18 {
+		t.Errorf("Expected param 18, got %v", violations[1].Params["param"])
+	}
+
+	expected := "validation failed: email: must be a valid email address; age: must be greater than or equal to 18"
+	if validationErr.Error() != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, validationErr.Error())
+	}
+}
+
+// TestValidationErrorErrOrNil tests that an empty validation error is nil
+func TestValidationErrorErrOrNil(t *testing.T) {
+	if err := NewValidationError().ErrOrNil(); err != nil {
+		t.Errorf("Expected nil error, got %v", err)
+	}
+
+	if err := NewValidationError(V("name", "required", "is required")).ErrOrNil(); err == nil {
+		t.Error("Expected non-nil error")
+	}
+}
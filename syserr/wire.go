@@ -0,0 +1,179 @@
+package syserr
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	serviceNameMu sync.RWMutex
+	serviceName   string
+)
+
+// SetServiceName sets the name recorded as origin of the errors encoded by this service
+func SetServiceName(name string) {
+	serviceNameMu.Lock()
+	defer serviceNameMu.Unlock()
+
+	serviceName = name
+}
+
+func getServiceName() string {
+	serviceNameMu.RLock()
+	defer serviceNameMu.RUnlock()
+
+	return serviceName
+}
+
+// wireError is the JSON wire format of an error.
+// Nodes without a code are plain errors, nodes with violations are validation errors.
+type wireError struct {
+	Code          Code         `json:"code,omitempty"`
+	Message       string       `json:"message,omitempty"`
+	PublicMessage string       `json:"public_message,omitempty"`
+	Fields        []*Field     `json:"fields,omitempty"`
+	Violations    []*Violation `json:"violations,omitempty"`
+	Origin        string       `json:"origin,omitempty"`
+	Retryable     *bool        `json:"retryable,omitempty"`
+	RetryAfterMs  int64        `json:"retry_after_ms,omitempty"`
+	Cause         *wireError   `json:"cause,omitempty"`
+}
+
+// Encode encodes the error to JSON with its code, messages, fields and origin service.
+// When includeCause is set, the wrapped error chain is encoded as well; otherwise the
+// internal messages of wrapped errors are dropped.
+func Encode(err error, includeCause bool) ([]byte, error) {
+	if err == nil {
+		return nil, New(InvalidArgumentCode, "cannot encode nil error")
+	}
+
+	return json.Marshal(encodeError(err, includeCause))
+}
+
+// Decode decodes an error encoded with Encode.
+// The stack trace is not transferred, the decoded error has none.
+func Decode(data []byte) (*Error, error) {
+	var node wireError
+	if err := json.Unmarshal(data, &node); err != nil {
+		return nil, Wrap(err, InvalidArgumentCode, "failed to decode error")
+	}
+
+	decoded := decodeError(&node)
+
+	if sErr, ok := decoded.(*Error); ok {
+		return sErr, nil
+	}
+
+	return &Error{
+		code:         GetCodeFromGenericError(decoded),
+		origin:       node.Origin,
+		WrappedError: decoded,
+	}, nil
+}
+
+// MarshalJSON encodes the error with its cause chain
+func (e *Error) MarshalJSON() ([]byte, error) {
+	return json.Marshal(encodeError(e, true))
+}
+
+// UnmarshalJSON decodes an error encoded with MarshalJSON or Encode
+func (e *Error) UnmarshalJSON(data []byte) error {
+	decoded, err := Decode(data)
+	if err != nil {
+		return err
+	}
+
+	*e = *decoded
+	return nil
+}
+
+func encodeError(err error, includeCause bool) *wireError {
+	var node *wireError
+
+	switch typedErr := err.(type) {
+	case *Error:
+		node = &wireError{
+			Code:          typedErr.code,
+			Message:       typedErr.Message,
+			PublicMessage: typedErr.publicMessage,
+			Fields:        redactFields(typedErr.fields),
+			Origin:        typedErr.origin,
+			Retryable:     typedErr.retryable,
+			RetryAfterMs:  typedErr.retryAfter.Milliseconds(),
+		}
+
+		if includeCause && typedErr.WrappedError != nil {
+			node.Cause = encodeCause(typedErr.WrappedError)
+		}
+	case *ValidationError:
+		node = &wireError{
+			Code:       ValidationCode,
+			Violations: typedErr.violations,
+		}
+	default:
+		node = flattenError(err)
+	}
+
+	if node.Origin == "" {
+		node.Origin = getServiceName()
+	}
+
+	return node
+}
+
+func encodeCause(err error) *wireError {
+	switch typedErr := err.(type) {
+	case *Error:
+		return encodeError(typedErr, true)
+	case *ValidationError:
+		return &wireError{
+			Code:       ValidationCode,
+			Violations: typedErr.violations,
+		}
+	default:
+		return flattenError(err)
+	}
+}
+
+// flattenError encodes a foreign error as a single node, keeping the code and fields found in its chain
+func flattenError(err error) *wireError {
+	var codeError interface{ Code() Code }
+	if !errors.As(err, &codeError) {
+		return &wireError{Message: err.Error()}
+	}
+
+	return &wireError{
+		Code:          codeError.Code(),
+		Message:       err.Error(),
+		PublicMessage: findPublicMessage(err),
+		Fields:        GetFieldsFromGenericError(err),
+	}
+}
+
+func decodeError(node *wireError) error {
+	if len(node.Violations) > 0 {
+		return NewValidationError(node.Violations...)
+	}
+
+	if node.Code == "" {
+		return errors.New(node.Message)
+	}
+
+	result := &Error{
+		Message:       node.Message,
+		publicMessage: node.PublicMessage,
+		origin:        node.Origin,
+		code:          node.Code,
+		fields:        node.Fields,
+		retryable:     node.Retryable,
+		retryAfter:    time.Duration(node.RetryAfterMs) * time.Millisecond,
+	}
+
+	if node.Cause != nil {
+		result.WrappedError = decodeError(node.Cause)
+	}
+
+	return result
+}
@@ -0,0 +1,126 @@
+package syserr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// TestEncodeDecode tests that code, messages, fields and cause chain survive encoding
+func TestEncodeDecode(t *testing.T) {
+	SetServiceName("payment-service")
+	defer SetServiceName("")
+
+	cause := Wrap(errors.New("card expired"), Code("payment_declined"), "gateway declined card", F("gateway", "stripe"))
+	err := Wrap(cause, Code("payment_declined"), "failed to charge order", F("order_id", "order-1")).
+		WithPublicMessage("payment declined")
+
+	encoded, encodeErr := Encode(err, true)
+	if encodeErr != nil {
+		t.Fatalf("Failed to encode error: %v", encodeErr)
+	}
+
+	decoded, decodeErr := Decode(encoded)
+	if decodeErr != nil {
+		t.Fatalf("Failed to decode error: %v", decodeErr)
+	}
+
+	if code := GetCodeFromGenericError(decoded); code != Code("payment_declined") {
+		t.Errorf("Expected code payment_declined, got %s", code)
+	}
+
+	if decoded.Error() != err.Error() {
+		t.Errorf("Expected message '%s', got '%s'", err.Error(), decoded.Error())
+	}
+
+	if message := GetPublicMessageFromGenericError(decoded); message != "payment declined" {
+		t.Errorf("Expected public message 'payment declined', got '%s'", message)
+	}
+
+	if decoded.Origin() != "payment-service" {
+		t.Errorf("Expected origin 'payment-service', got '%s'", decoded.Origin())
+	}
+
+	fields := GetFieldsFromGenericError(decoded)
+	if len(fields) != 2 || fields[0].Key != "order_id" || fields[1].Key != "gateway" {
+		t.Errorf("Expected order_id and gateway fields, got %v", fields)
+	}
+}
+
+// TestEncodeWithoutCause tests that wrapped internal messages are dropped without the cause chain
+func TestEncodeWithoutCause(t *testing.T) {
+	err := Wrap(errors.New("pq: connection refused"), InternalCode, "failed to load user")
+
+	encoded, _ := Encode(err, false)
+	decoded, decodeErr := Decode(encoded)
+	if decodeErr != nil {
+		t.Fatalf("Failed to decode error: %v", decodeErr)
+	}
+
+	if decoded.Error() != "failed to load user" {
+		t.Errorf("Expected message 'failed to load user', got '%s'", decoded.Error())
+	}
+}
+
+// TestEncodeForeignWrapper tests encoding errors wrapped with fmt.Errorf and validation errors
+func TestEncodeForeignWrapper(t *testing.T) {
+	err := fmt.Errorf("handler failed: %w", New(NotFoundCode, "user not found", F("user_id", 1)))
+
+	encoded, _ := Encode(err, true)
+	decoded, _ := Decode(encoded)
+
+	if code := decoded.Code(); code != NotFoundCode {
+		t.Errorf("Expected code %s, got %s", NotFoundCode, code)
+	}
+
+	if decoded.Error() != err.Error() {
+		t.Errorf("Expected message '%s', got '%s'", err.Error(), decoded.Error())
+	}
+
+	validationErr := Wrap(NewValidationError(V("email", "required", "is required")), ValidationCode, "invalid request")
+	data, marshalErr := json.Marshal(validationErr)
+	if marshalErr != nil {
+		t.Fatalf("Failed to marshal error: %v", marshalErr)
+	}
+
+	var unmarshaled Error
+	if err := json.Unmarshal(data, &unmarshaled); err != nil {
+		t.Fatalf("Failed to unmarshal error: %v", err)
+	}
+
+	violations := GetViolationsFromGenericError(&unmarshaled)
+	if len(violations) != 1 || violations[0].Field != "email" {
+		t.Errorf("Expected email violation, got %v", violations)
+	}
+}
+
+// TestEncodeDecodeRetry tests that the retry classification and hint are transferred
+func TestEncodeDecodeRetry(t *testing.T) {
+	err := Wrap(New(InternalCode, "upstream busy").WithRetryAfter(1500*time.Millisecond), InternalCode, "failed to charge").
+		WithRetryable(true)
+
+	data, encodeErr := Encode(err, true)
+	if encodeErr != nil {
+		t.Fatalf("Encode failed: %v", encodeErr)
+	}
+
+	decoded, decodeErr := Decode(data)
+	if decodeErr != nil {
+		t.Fatalf("Decode failed: %v", decodeErr)
+	}
+
+	if retryable, ok := decoded.Retryable(); !ok || !retryable {
+		t.Errorf("Expected the error to be marked retryable")
+	}
+	if retryAfter := GetRetryAfterFromGenericError(decoded); retryAfter != 1500*time.Millisecond {
+		t.Errorf("Expected retry-after 1.5s, got %v", retryAfter)
+	}
+
+	permanent, _ := Encode(New(InternalCode, "corrupt").WithRetryable(false), false)
+	decoded, _ = Decode(permanent)
+	if !IsPermanent(decoded) {
+		t.Errorf("Expected the decoded error to be permanent")
+	}
+}